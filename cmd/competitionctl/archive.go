@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/archive"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+// exportArchive exports the whole competition to an archive, along with the
+// problems and final standings of each division.
+func exportArchive(ctx Context) error {
+	path := pflag.Arg(1)
+	if path == "" {
+		return fmt.Errorf("usage: export [path]")
+	}
+
+	// Everything is read in one transaction, so that the archive is
+	// consistent even if the server is running.
+	var a *archive.Archive
+	err := ctx.database.Tx(func(q *db.Queries) error {
+		var err error
+		a, err = archive.Export(ctx, q, time.Now())
+		if err != nil {
+			return err
+		}
+		return exportDivisions(ctx, q, a)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.Write(path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	log.Printf("exported %d teams, %d submissions and %d point entries to %s\n",
+		len(a.Teams), len(a.Submissions), len(a.Points), path)
+	return nil
+}
+
+// exportDivisions adds the problems and standings of each division to the
+// archive.
+func exportDivisions(ctx Context, q *db.Queries, a *archive.Archive) error {
+	tiebreakers := ctx.config.Leaderboard.TiebreakRules()
+
+	for _, division := range ctx.config.AllDivisions() {
+		problems, err := newScheduledProblemSet(ctx, &division)
+		if err != nil {
+			return err
+		}
+
+		d := archive.Division{
+			ID:   division.ID,
+			Name: division.Name,
+		}
+
+		for i, module := range division.Problems.Modules {
+			p := archive.Problem{
+				ID:         module.ProblemID(),
+				ReleasedAt: problems.ProblemStartTime(i),
+				Module:     module,
+			}
+
+			readme, err := os.ReadFile(module.README)
+			if err != nil {
+				log.Printf("warning: not archiving the README of %q: %v\n", p.ID, err)
+			} else {
+				p.README = archive.READMEPath(division.ID, p.ID)
+				a.Files[p.README] = readme
+			}
+
+			d.Problems = append(d.Problems, p)
+		}
+
+		standings, err := server.Standings(ctx, q, division.ID, tiebreakers)
+		if err != nil {
+			return fmt.Errorf("failed to get standings of division %q: %w", division.ID, err)
+		}
+		for _, standing := range standings {
+			d.Standings = append(d.Standings, archive.Standing{
+				Rank:     standing.Rank,
+				TeamName: standing.TeamName,
+				Points:   standing.Points,
+			})
+		}
+
+		a.Divisions = append(a.Divisions, d)
+	}
+
+	return nil
+}
+
+// importArchive imports an archive into the database, which must be empty.
+func importArchive(ctx Context) error {
+	path := pflag.Arg(1)
+	if path == "" {
+		return fmt.Errorf("usage: import [path]")
+	}
+
+	a, err := archive.Read(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	// Problems aren't imported, so the config should still have the problems
+	// that the archived submissions and points refer to.
+	for _, division := range a.Divisions {
+		config := ctx.config.Division(division.ID)
+		if config == nil {
+			log.Printf("warning: division %q of the archive is not configured\n", division.ID)
+			continue
+		}
+
+		configured := make(map[string]bool, len(config.Problems.Modules))
+		for _, module := range config.Problems.Modules {
+			configured[module.ProblemID()] = true
+		}
+		for _, p := range division.Problems {
+			if !configured[p.ID] {
+				log.Printf("warning: problem %q of division %q is not configured\n", p.ID, division.ID)
+			}
+		}
+	}
+
+	if err := archive.Import(ctx, ctx.database, a); err != nil {
+		return fmt.Errorf("failed to import archive: %w", err)
+	}
+
+	if err := ctx.database.Tx(func(q *db.Queries) error {
+		return ctx.audit(q, "import", map[string]any{
+			"path":        path,
+			"exported_at": a.ExportedAt,
+		}, nil, nil)
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("imported %d teams, %d submissions and %d point entries exported at %s\n",
+		len(a.Teams), len(a.Submissions), len(a.Points), a.ExportedAt.Format(time.DateTime))
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+// auditFilter selects audit log entries. Empty fields match everything.
+type auditFilter struct {
+	actor  string
+	action string
+	team   string
+	since  time.Time
+}
+
+func parseAuditFilter(ctx Context, args []string) (auditFilter, error) {
+	var f auditFilter
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return f, fmt.Errorf("invalid filter %q, must be key=value", arg)
+		}
+		switch key {
+		case "actor":
+			f.actor = value
+		case "action":
+			f.action = value
+		case "team":
+			f.team = value
+		case "since":
+			if d, err := time.ParseDuration(value); err == nil {
+				f.since = ctx.clock.Now().Add(-d)
+				continue
+			}
+			t, err := time.ParseInLocation(time.DateTime, value, time.Local)
+			if err != nil {
+				t, err = time.Parse(time.RFC3339, value)
+			}
+			if err != nil {
+				return f, fmt.Errorf("invalid since %q, must be a duration or a time", value)
+			}
+			f.since = t
+		default:
+			return f, fmt.Errorf("unknown filter %q, must be actor, action, team or since", key)
+		}
+	}
+	return f, nil
+}
+
+// matches returns true if the entry matches the filter. Actions match by
+// their first word too, so that "schedule" matches "schedule delay".
+func (f auditFilter) matches(entry db.AuditLog, arguments map[string]any) bool {
+	if f.actor != "" && entry.Actor != f.actor {
+		return false
+	}
+	if f.action != "" && entry.Action != f.action && !strings.HasPrefix(entry.Action, f.action+" ") {
+		return false
+	}
+	if f.team != "" && arguments["team"] != f.team {
+		return false
+	}
+	if !f.since.IsZero() && entry.CreatedAt.Time().Before(f.since) {
+		return false
+	}
+	if division, ok := arguments["division"].(string); ok && !inDivision(division) {
+		return false
+	}
+	return true
+}
+
+func audit(ctx Context) error {
+	filter, err := parseAuditFilter(ctx, pflag.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	entries, err := ctx.database.ListAuditLog(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tTime\tActor\tAction\tArguments\tBefore\tAfter\n")
+	fmt.Fprintf(w, "--\t----\t-----\t------\t---------\t------\t-----\n")
+
+	for _, entry := range entries {
+		var arguments map[string]any
+		json.Unmarshal([]byte(entry.Arguments), &arguments)
+
+		if !filter.matches(entry, arguments) {
+			continue
+		}
+
+		before := "-"
+		if entry.Before.Valid {
+			before = entry.Before.String
+		}
+		after := "-"
+		if entry.After.Valid {
+			after = entry.After.String
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			entry.CreatedAt.Time().In(time.Local).Format(time.DateTime),
+			entry.Actor,
+			entry.Action,
+			entry.Arguments,
+			before,
+			after)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+// backup takes a snapshot of the database while the server keeps running.
+// Without a path, the snapshot is written to the backups dir, where the
+// oldest snapshots beyond backups.keep are deleted.
+func backup(ctx Context) error {
+	if pflag.Arg(1) == "list" {
+		return backupList(ctx)
+	}
+
+	path := pflag.Arg(1)
+	if path == "" {
+		var err error
+		path, err = server.TakeSnapshot(ctx, ctx.database, ctx.config.Backups)
+		if err != nil {
+			return fmt.Errorf("failed to take snapshot: %w", err)
+		}
+	} else {
+		if err := ctx.database.Backup(ctx, path); err != nil {
+			return fmt.Errorf("failed to take snapshot: %w", err)
+		}
+	}
+
+	log.Printf("wrote snapshot %s (schema version %d)\n", path, db.SchemaVersion())
+	return nil
+}
+
+func backupList(ctx Context) error {
+	if ctx.config.Backups.Dir == "" {
+		return fmt.Errorf("no backups dir is configured")
+	}
+
+	snapshots, err := server.ListSnapshots(ctx.config.Backups.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Snapshot\tSize\tTaken At\n")
+	fmt.Fprintf(w, "--------\t----\t--------\n")
+	for _, path := range snapshots {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%d KiB\t%s\n", path, info.Size()/1024, info.ModTime().Format(time.DateTime))
+	}
+	return w.Flush()
+}
+
+// restore replaces the database with a snapshot. It runs before the database
+// is opened, since the database must not be open while it is replaced. The
+// current database is snapshotted first, so a restore can be undone.
+func restore(ctx context.Context, cfg *config.Config) error {
+	snapshot := pflag.Arg(1)
+	if snapshot == "" {
+		return fmt.Errorf("usage: restore [snapshot]")
+	}
+
+	version, err := db.CheckSnapshot(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", snapshot, err)
+	}
+	log.Printf("snapshot %s has schema version %d, this build has %d\n", snapshot, version, db.SchemaVersion())
+
+	database, err := db.Open(cfg.DatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	dir := cfg.Backups.Dir
+	if dir == "" {
+		dir = filepath.Dir(strings.TrimPrefix(cfg.DatabaseURL(), "sqlite://"))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		database.Close()
+		return fmt.Errorf("failed to create backups dir: %w", err)
+	}
+	previous := filepath.Join(dir, server.SnapshotName("pre-restore", time.Now()))
+
+	err = database.Backup(ctx, previous)
+	database.Close()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the current database: %w", err)
+	}
+	log.Printf("saved the current database to %s\n", previous)
+
+	if err := db.Restore(ctx, snapshot, cfg.DatabaseURL()); err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+
+	// The restore is recorded in the restored database, where it is seen.
+	database, err = db.Open(cfg.DatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to open restored database: %w", err)
+	}
+	defer database.Close()
+
+	auditCtx := Context{Context: ctx, config: cfg, database: database, clock: cfg.Clock.Clock()}
+	if err := database.Tx(func(q *db.Queries) error {
+		return auditCtx.audit(q, "restore", map[string]any{
+			"snapshot": snapshot,
+		}, map[string]any{"saved_to": previous}, nil)
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("restored the database from %s\n", snapshot)
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -14,7 +15,9 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"dev.acmcsuf.com/march-madness-2024/internal/clock"
 	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server"
 	"dev.acmcsuf.com/march-madness-2024/server/db"
 	"github.com/spf13/pflag"
 )
@@ -22,11 +25,21 @@ import (
 var (
 	configPath = "config.json"
 	verbose    = false
+	division   = ""
+	apply      = false
+	replay     = 0
+	actor      = os.Getenv("USER")
+	force      = false
 )
 
 func main() {
 	pflag.StringVarP(&configPath, "config", "c", configPath, "path to config file")
 	pflag.BoolVarP(&verbose, "verbose", "v", verbose, "enable verbose logging")
+	pflag.StringVarP(&division, "division", "d", division, "division to operate on (default: all or the only division)")
+	pflag.BoolVar(&apply, "apply", apply, "apply the changes of rescore instead of only printing them")
+	pflag.IntVar(&replay, "replay", replay, "scoring version to replay the submission history under in scoring simulate")
+	pflag.StringVar(&actor, "actor", actor, "admin name recorded with the points that a command adds")
+	pflag.BoolVar(&force, "force", force, "allow schedule delay to hide a problem that is already released")
 	pflag.Usage = func() {
 		log.SetFlags(0)
 		log.Println("Usage:")
@@ -55,6 +68,78 @@ type Context struct {
 	context.Context
 	config   *config.Config
 	database *db.Database
+	clock    clock.Clock
+}
+
+// now returns the current time as a database timestamp.
+func (ctx Context) now() db.DateTime {
+	return db.NewDateTime(ctx.clock.Now())
+}
+
+// actor returns the admin recorded with the points that a command adds.
+func (ctx Context) actor() sql.NullString {
+	return sql.NullString{String: actor, Valid: actor != ""}
+}
+
+// audit records a change made by the command in the audit log, in the same
+// transaction as the change.
+func (ctx Context) audit(q *db.Queries, action string, arguments, before, after any) error {
+	actor := actor
+	if actor == "" {
+		actor = "competitionctl"
+	}
+	return server.RecordAudit(ctx, q, server.AuditEntry{
+		Actor:     actor,
+		Action:    action,
+		Arguments: arguments,
+		Before:    before,
+		After:     after,
+	}, ctx.clock.Now())
+}
+
+// auditPoints converts points entries to their audit log values.
+func auditPoints(points ...db.TeamPoint) []map[string]any {
+	values := make([]map[string]any, len(points))
+	for i, p := range points {
+		values[i] = map[string]any{
+			"id":       p.ID,
+			"points":   p.Points,
+			"reason":   p.Reason,
+			"added_at": p.AddedAt.Time(),
+		}
+	}
+	return values
+}
+
+// division returns the division selected using the --division flag. If the
+// competition has only one division, it is selected by default.
+func (ctx Context) division() (*config.DivisionConfig, error) {
+	divisions := ctx.config.AllDivisions()
+	if division == "" && len(divisions) == 1 {
+		return &divisions[0], nil
+	}
+	if division == "" {
+		return nil, fmt.Errorf("missing --division, must be one of %s", divisionIDs(divisions))
+	}
+	d := ctx.config.Division(division)
+	if d == nil {
+		return nil, fmt.Errorf("unknown division %q, must be one of %s", division, divisionIDs(divisions))
+	}
+	return d, nil
+}
+
+func divisionIDs(divisions []config.DivisionConfig) string {
+	ids := make([]string, len(divisions))
+	for i, d := range divisions {
+		ids[i] = strconv.Quote(d.ID)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// inDivision returns true if the given team division matches the --division
+// flag. All divisions match if the flag is not given.
+func inDivision(teamDivision string) bool {
+	return division == "" || division == teamDivision
 }
 
 func run(ctx context.Context) error {
@@ -63,16 +148,25 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	db, err := db.Open(config.Paths.Database)
+	if pflag.Arg(0) == "restore" {
+		return restore(ctx, config)
+	}
+
+	db, err := db.Open(config.DatabaseURL())
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer db.Close()
 
+	if config.Clock.IsSimulated() && config.Clock.Anchor.IsZero() {
+		log.Println("warning: clock.anchor is not set, the simulated time may differ from the server's")
+	}
+
 	context := Context{
 		Context:  ctx,
 		config:   config,
 		database: db,
+		clock:    config.Clock.Clock(),
 	}
 	switch pflag.Arg(0) {
 	case "hackathon-set-winner":
@@ -87,6 +181,28 @@ func run(ctx context.Context) error {
 		return teamInviteCode(context)
 	case "list-points":
 		return pointsList(context)
+	case "schedule":
+		return schedule(context)
+	case "void":
+		return voidProblem(context)
+	case "list-voided":
+		return listVoided(context)
+	case "migrate-problem-ids":
+		return migrateProblemIDs(context)
+	case "rescore":
+		return rescore(context)
+	case "reveal":
+		return reveal(context)
+	case "scoring":
+		return scoring(context)
+	case "backup":
+		return backup(context)
+	case "export":
+		return exportArchive(context)
+	case "import":
+		return importArchive(context)
+	case "audit":
+		return audit(context)
 	default:
 		pflag.Usage()
 		return fmt.Errorf("missing or invalid command %q", pflag.Arg(0))
@@ -96,10 +212,29 @@ func run(ctx context.Context) error {
 var commandsHelp = []string{
 	"hackathon-set-winner [team] [0|1|2|3] [points] set hackathon winner (0 = no winner)",
 	"award-points [team] [points] [reason]          award or remove points",
-	"list-teams                                     list teams",
+	"list-teams                                     list teams (in --division)",
 	"delete-team [team]                             delete team",
 	"invite-code [team]                             get invite code for team",
-	"list-points                                    list points",
+	"list-points                                    list points (in --division)",
+	"schedule list                                  list schedule overrides (of --division)",
+	"schedule status                                show problem release times",
+	"schedule pause|resume [reason]                 pause or resume problem releases",
+	"schedule delay [day] [duration] [reason]       delay the release of a problem (--force if released)",
+	"schedule release [day|next] [reason]           release a problem now",
+	"void [day] [1|2|all] [credit] [reason]         void a problem, removing its points and crediting everyone",
+	"list-voided                                    list voided problems (of --division)",
+	"migrate-problem-ids [old new]                  rename problem IDs (default: README paths to config IDs)",
+	"rescore [--apply]                              recompute solve points after a scoring change (of --division)",
+	"reveal status                                  show the reveal order of the frozen leaderboard (of --division)",
+	"reveal start|next [n]|all|reset                start the reveal, reveal the next n or all teams, or refreeze",
+	"scoring simulate [offset...]                   print the points of every scoring version and curve at the offsets",
+	"scoring simulate --replay [version]            compare the standings under another scoring version (of --division)",
+	"backup [path]                                  snapshot the database while the server runs (default: into backups.dir)",
+	"backup list                                    list the snapshots in backups.dir",
+	"restore [snapshot]                             replace the database with a snapshot (stop the server first)",
+	"export [path]                                  export the whole competition to a zip archive (or JSON if path ends in .json)",
+	"import [path]                                  import an exported archive into an empty database",
+	"audit [actor=|action=|team=|since=...]         list the audit log of admin changes (of --division)",
 }
 
 func hackathonSetWinner(ctx Context) error {
@@ -123,36 +258,59 @@ func hackathonSetWinner(ctx Context) error {
 		}
 	}
 
-	if err := ctx.database.SetHackathonWinner(ctx, db.SetHackathonWinnerParams{
-		TeamName: team,
-		WonRank: sql.NullInt64{
-			Int64: int64(place),
-			Valid: place != 0,
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to set hackathon winner: %w", err)
-	}
-
-	if place == 0 {
-		_, err := ctx.database.RemovePointsByReason(ctx, db.RemovePointsByReasonParams{
-			TeamName: team,
-			Reason:   "hackathon",
-		})
+	return ctx.database.Tx(func(q *db.Queries) error {
+		submission, err := q.HackathonSubmission(ctx, team)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("team %q has no hackathon submission", team)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to remove points: %w", err)
+			return fmt.Errorf("failed to get hackathon submission: %w", err)
 		}
-	} else {
-		_, err := ctx.database.AddPoints(ctx, db.AddPointsParams{
+
+		if err := q.SetHackathonWinner(ctx, db.SetHackathonWinnerParams{
 			TeamName: team,
-			Points:   points,
-			Reason:   "hackathon",
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add points: %w", err)
+			WonRank: sql.NullInt64{
+				Int64: int64(place),
+				Valid: place != 0,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to set hackathon winner: %w", err)
 		}
-	}
 
-	return nil
+		before := map[string]any{"won_rank": submission.WonRank.Int64}
+		after := map[string]any{"won_rank": place}
+
+		if place == 0 {
+			removed, err := q.RemovePointsByReason(ctx, db.RemovePointsByReasonParams{
+				TeamName: team,
+				Source:   string(server.PointsFromHackathon),
+				Reason:   "hackathon",
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove points: %w", err)
+			}
+			before["points"] = auditPoints(removed...)
+		} else {
+			added, err := q.AddPoints(ctx, db.AddPointsParams{
+				TeamName: team,
+				Points:   points,
+				Reason:   "hackathon",
+				Source:   string(server.PointsFromHackathon),
+				Actor:    ctx.actor(),
+				AddedAt:  ctx.now(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add points: %w", err)
+			}
+			after["points"] = auditPoints(added)
+		}
+
+		return ctx.audit(q, "hackathon-set-winner", map[string]any{
+			"team":   team,
+			"place":  place,
+			"points": points,
+		}, before, after)
+	})
 }
 
 func awardPoints(ctx Context) error {
@@ -168,33 +326,54 @@ func awardPoints(ctx Context) error {
 		return fmt.Errorf("hackathon-specific points must be set with hackathon-set-winner")
 	}
 
-	deletedPoints, deleteErr := ctx.database.RemovePointsByReason(ctx, db.RemovePointsByReasonParams{
-		TeamName: team,
-		Reason:   reason,
-	})
-	if deleteErr != nil {
-		return fmt.Errorf("failed to remove points: %w", deleteErr)
-	}
-
-	if points > 0 {
-		if len(deletedPoints) > 0 {
-			log.Printf("team %q has %d points to delete for reason %q\n", team, len(deletedPoints), reason)
-		}
-		_, err = ctx.database.AddPoints(ctx, db.AddPointsParams{
+	return ctx.database.Tx(func(q *db.Queries) error {
+		// Only points awarded by admins are replaced, so that the entries of
+		// solves can't be deleted by reusing their reason.
+		deletedPoints, err := q.RemovePointsByReason(ctx, db.RemovePointsByReasonParams{
 			TeamName: team,
-			Points:   points,
+			Source:   string(server.PointsFromAdmin),
 			Reason:   reason,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to add points: %w", err)
+			return fmt.Errorf("failed to remove points: %w", err)
 		}
-	} else {
-		if len(deletedPoints) == 0 {
-			log.Printf("team %q has no points to delete for reason %q\n", team, reason)
+
+		// Earlier points with the same reason are replaced, which the audit
+		// log keeps a record of.
+		var after any
+		if points > 0 {
+			if len(deletedPoints) > 0 {
+				log.Printf("team %q has %d points to delete for reason %q\n", team, len(deletedPoints), reason)
+			}
+			added, err := q.AddPoints(ctx, db.AddPointsParams{
+				TeamName: team,
+				Points:   points,
+				Reason:   reason,
+				Source:   string(server.PointsFromAdmin),
+				Actor:    ctx.actor(),
+				AddedAt:  ctx.now(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add points: %w", err)
+			}
+			after = auditPoints(added)
+		} else {
+			if len(deletedPoints) == 0 {
+				log.Printf("team %q has no points to delete for reason %q\n", team, reason)
+			}
 		}
-	}
 
-	return nil
+		var before any
+		if len(deletedPoints) > 0 {
+			before = auditPoints(deletedPoints...)
+		}
+
+		return ctx.audit(q, "award-points", map[string]any{
+			"team":   team,
+			"points": points,
+			"reason": reason,
+		}, before, after)
+	})
 }
 
 func teamsList(ctx Context) error {
@@ -203,39 +382,60 @@ func teamsList(ctx Context) error {
 		return fmt.Errorf("failed to list teams: %w", err)
 	}
 
-	teamPoints, err := ctx.database.TeamPointsTotal(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get team points: %w", err)
-	}
+	tiebreakers := ctx.config.Leaderboard.TiebreakRules()
 
 	var b strings.Builder
 	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "Team\tMembers\tPoints\tCreated At\n")
-	fmt.Fprintf(w, "----\t-------\t------\t----------\n")
+	fmt.Fprintf(w, "Rank\tTeam\tDivision\tMembers\tPoints")
+	for _, tiebreaker := range tiebreakers {
+		fmt.Fprintf(w, "\tTiebreak (%s)", tiebreaker)
+	}
+	fmt.Fprintf(w, "\tCreated At\n")
+	fmt.Fprintf(w, "----\t----\t--------\t-------\t------")
+	for range tiebreakers {
+		fmt.Fprintf(w, "\t--------")
+	}
+	fmt.Fprintf(w, "\t----------\n")
 
-	for _, team := range teams {
-		var membersString string
-		if members, err := ctx.database.ListTeamMembers(ctx, team.TeamName); err != nil {
-			membersString = fmt.Sprintf("(error: %v)", err)
-		} else {
-			strs := make([]string, len(members))
-			for i, member := range members {
-				strs[i] = member.Username
-				if member.IsLeader {
-					strs[i] += " (leader)"
-				}
-			}
-			membersString = strings.Join(strs, ", ")
+	// Teams are listed by division in the order of their standings.
+	for _, division := range ctx.config.AllDivisions() {
+		if !inDivision(division.ID) {
+			continue
 		}
 
-		pointsIx := slices.IndexFunc(teamPoints, func(r db.TeamPointsTotalRow) bool {
-			return r.TeamName == team.TeamName
-		})
-		points := teamPoints[pointsIx].Points.Float64
+		standings, err := server.Standings(ctx, ctx.database.Queries, division.ID, tiebreakers)
+		if err != nil {
+			return fmt.Errorf("failed to get standings: %w", err)
+		}
 
-		fmt.Fprintf(w,
-			"%s\t%s\t%.0f\t%v\n",
-			team.TeamName, membersString, points, team.CreatedAt.Time().In(time.Local))
+		for _, standing := range standings {
+			teamIx := slices.IndexFunc(teams, func(t db.ListTeamsRow) bool {
+				return t.TeamName == standing.TeamName
+			})
+			team := teams[teamIx]
+
+			var membersString string
+			if members, err := ctx.database.ListTeamMembers(ctx, team.TeamName); err != nil {
+				membersString = fmt.Sprintf("(error: %v)", err)
+			} else {
+				strs := make([]string, len(members))
+				for i, member := range members {
+					strs[i] = member.Username
+					if member.IsLeader {
+						strs[i] += " (leader)"
+					}
+				}
+				membersString = strings.Join(strs, ", ")
+			}
+
+			fmt.Fprintf(w,
+				"%d\t%s\t%s\t%s\t%.0f",
+				standing.Rank, team.TeamName, team.Division, membersString, math.Floor(standing.Points))
+			for _, tiebreaker := range tiebreakers {
+				fmt.Fprintf(w, "\t%s", standing.TiebreakValue(tiebreaker))
+			}
+			fmt.Fprintf(w, "\t%v\n", team.CreatedAt.Time().In(time.Local))
+		}
 	}
 
 	w.Flush()
@@ -247,12 +447,37 @@ func teamsList(ctx Context) error {
 func teamsDelete(ctx Context) error {
 	team := pflag.Arg(1)
 
-	t, err := ctx.database.DropTeam(ctx, team)
+	var t db.Team
+	err := ctx.database.Tx(func(q *db.Queries) error {
+		members, err := q.ListTeamMembers(ctx, team)
+		if err != nil {
+			return fmt.Errorf("failed to list team members: %w", err)
+		}
+
+		t, err = q.DropTeam(ctx, team)
+		if err != nil {
+			return fmt.Errorf("failed to drop team: %w", err)
+		}
+
+		usernames := make([]string, len(members))
+		for i, member := range members {
+			usernames[i] = member.Username
+		}
+
+		return ctx.audit(q, "delete-team", map[string]any{
+			"team": team,
+		}, map[string]any{
+			"team":       t.TeamName,
+			"division":   t.Division,
+			"created_at": t.CreatedAt.Time(),
+			"members":    usernames,
+		}, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to drop team: %w", err)
+		return err
 	}
 
-	fmt.Printf("dropped team %q created at %v\n", t.TeamName, t.CreatedAt)
+	fmt.Printf("dropped team %q created at %v\n", t.TeamName, t.CreatedAt.Time())
 	return nil
 }
 
@@ -269,16 +494,52 @@ func teamInviteCode(ctx Context) error {
 }
 
 func pointsList(ctx Context) error {
-	points, err := ctx.database.TeamPointsHistory(ctx)
+	teams, err := ctx.database.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	teamDivisions := make(map[string]string, len(teams))
+	for _, team := range teams {
+		teamDivisions[team.TeamName] = team.Division
+	}
+
+	points, err := ctx.database.ListPoints(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get points: %w", err)
 	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tAdded At\tTeam\tPoints\tSource\tProblem\tSubmission\tActor\tReason\n")
+	fmt.Fprintf(w, "--\t--------\t----\t------\t------\t-------\t----------\t-----\t------\n")
+
 	for _, pt := range points {
-		fmt.Printf(
-			"%v: %s +%f\n",
-			pt.AddedAt.Time().In(time.Local),
-			pt.TeamName,
-			math.Floor(pt.Points))
+		if !inDivision(teamDivisions[pt.TeamName]) {
+			continue
+		}
+
+		problem := "-"
+		if pt.ProblemID.Valid {
+			problem = pt.ProblemID.String
+		}
+		submission := "-"
+		if pt.SubmissionID.Valid {
+			submission = strconv.FormatInt(pt.SubmissionID.Int64, 10)
+		}
+		actor := "-"
+		if pt.Actor.Valid {
+			actor = pt.Actor.String
+		}
+
+		fmt.Fprintf(w,
+			"%d\t%v\t%s\t%+.2f\t%s\t%s\t%s\t%s\t%s\n",
+			pt.ID, pt.AddedAt.Time().In(time.Local), pt.TeamName, pt.Points,
+			pt.Source, problem, submission, actor, pt.Reason)
 	}
+
+	w.Flush()
+	fmt.Print(b.String())
+
 	return nil
 }
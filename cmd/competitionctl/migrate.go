@@ -0,0 +1,99 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+type problemIDRename struct {
+	old string
+	new string
+}
+
+// migrateProblemIDs rewrites the problem IDs stored in the database. Without
+// arguments, problems that have an explicit ID in the config are renamed from
+// their README path, which was the ID used before explicit IDs existed.
+func migrateProblemIDs(ctx Context) error {
+	var renames []problemIDRename
+	switch pflag.NArg() {
+	case 1:
+		for _, division := range ctx.config.AllDivisions() {
+			if !inDivision(division.ID) {
+				continue
+			}
+			for _, module := range division.Problems.Modules {
+				if module.ID != "" && module.ID != module.README {
+					renames = append(renames, problemIDRename{module.README, module.ID})
+				}
+			}
+		}
+	case 3:
+		renames = append(renames, problemIDRename{pflag.Arg(1), pflag.Arg(2)})
+	default:
+		return fmt.Errorf("usage: migrate-problem-ids [old new]")
+	}
+
+	if len(renames) == 0 {
+		log.Println("no problems have explicit IDs, nothing to migrate")
+		return nil
+	}
+
+	return ctx.database.Tx(func(q *db.Queries) error {
+		for _, rename := range renames {
+			if _, err := q.RenameProblemStartsProblemID(ctx, db.RenameProblemStartsProblemIDParams{
+				NewProblemID: rename.new,
+				OldProblemID: rename.old,
+			}); err != nil {
+				return fmt.Errorf("failed to rename problem starts of %q: %w", rename.old, err)
+			}
+
+			for _, part := range []string{"/part1", "/part2"} {
+				oldID := rename.old + part
+				newID := rename.new + part
+
+				submissions, err := q.RenameSubmissionsProblemID(ctx, db.RenameSubmissionsProblemIDParams{
+					NewProblemID: newID,
+					OldProblemID: oldID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to rename submissions of %q: %w", oldID, err)
+				}
+
+				points, err := q.RenamePointsProblemID(ctx, db.RenamePointsProblemIDParams{
+					NewProblemID: sql.NullString{String: newID, Valid: true},
+					OldProblemID: sql.NullString{String: oldID, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to rename points of %q: %w", oldID, err)
+				}
+
+				if _, err := q.RenameVoidedProblemID(ctx, db.RenameVoidedProblemIDParams{
+					NewProblemID: newID,
+					OldProblemID: oldID,
+				}); err != nil {
+					return fmt.Errorf("failed to rename voided problem %q: %w", oldID, err)
+				}
+
+				if _, err := q.RenameFirstSolvesProblemID(ctx, db.RenameFirstSolvesProblemIDParams{
+					NewProblemID: newID,
+					OldProblemID: oldID,
+				}); err != nil {
+					return fmt.Errorf("failed to rename first solves of %q: %w", oldID, err)
+				}
+
+				log.Printf("renamed %q to %q: %d submissions, %d point entries\n",
+					oldID, newID, submissions, points)
+			}
+		}
+
+		arguments := make(map[string]string, len(renames))
+		for _, rename := range renames {
+			arguments[rename.old] = rename.new
+		}
+		return ctx.audit(q, "migrate-problem-ids", arguments, nil, nil)
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/tabwriter"
+
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+)
+
+func rescore(ctx Context) error {
+	divisionConfig, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	problems, err := loadScheduledProblemSet(ctx)
+	if err != nil {
+		return err
+	}
+
+	division := &server.Division{
+		ID:       divisionConfig.ID,
+		Name:     divisionConfig.Name,
+		Problems: problems,
+	}
+
+	// Compute and apply the adjustments in the same transaction, so that no
+	// solve can land in between.
+	var adjustments []server.PointsAdjustment
+	err = ctx.database.Tx(func(q *db.Queries) error {
+		adjustments, err = server.Rescore(ctx, q, division)
+		if err != nil {
+			return fmt.Errorf("failed to rescore: %w", err)
+		}
+
+		if !apply || len(adjustments) == 0 {
+			return nil
+		}
+
+		if err := server.ApplyPointsAdjustments(ctx, q, adjustments, ctx.clock.Now(), actor); err != nil {
+			return err
+		}
+
+		deltas := make(map[string]float64)
+		for _, adjustment := range adjustments {
+			deltas[adjustment.TeamName] += adjustment.Delta()
+		}
+		return ctx.audit(q, "rescore", map[string]any{
+			"division": division.ID,
+		}, nil, map[string]any{
+			"adjustments": len(adjustments),
+			"deltas":      deltas,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	printPointsAdjustments(adjustments)
+
+	switch {
+	case len(adjustments) == 0:
+		log.Println("all points are up to date")
+	case apply:
+		log.Printf("applied %d adjustments\n", len(adjustments))
+	default:
+		log.Printf("dry run, use --apply to apply the %d adjustments\n", len(adjustments))
+	}
+
+	return nil
+}
+
+func printPointsAdjustments(adjustments []server.PointsAdjustment) {
+	if len(adjustments) == 0 {
+		return
+	}
+
+	var teams []string
+	totals := make(map[string]float64)
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Team\tProblem\tSource\tCurrent\tNew\tChange\n")
+	fmt.Fprintf(w, "----\t-------\t------\t-------\t---\t------\n")
+
+	for _, a := range adjustments {
+		fmt.Fprintf(w,
+			"%s\t%s\t%s\t%.2f\t%.2f\t%+.2f\n",
+			a.TeamName, a.ProblemID, a.Source, a.Current, a.Expected, a.Delta())
+
+		if _, ok := totals[a.TeamName]; !ok {
+			teams = append(teams, a.TeamName)
+		}
+		totals[a.TeamName] += a.Delta()
+	}
+
+	fmt.Fprintf(w, "\t\t\t\t\t\n")
+	fmt.Fprintf(w, "Team\t\t\t\t\tTotal Change\n")
+	fmt.Fprintf(w, "----\t\t\t\t\t------------\n")
+	for _, team := range teams {
+		fmt.Fprintf(w, "%s\t\t\t\t\t%+.2f\n", team, totals[team])
+	}
+
+	w.Flush()
+	fmt.Print(b.String())
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+func reveal(ctx Context) error {
+	if ctx.config.Leaderboard.FreezeAt.IsZero() {
+		return fmt.Errorf("the leaderboard has no freeze, set leaderboard.freeze_at first")
+	}
+
+	switch pflag.Arg(1) {
+	case "status":
+		return revealStatus(ctx)
+	case "start":
+		return revealAdvance(ctx, 0, false)
+	case "next":
+		n := 1
+		if pflag.Arg(2) != "" {
+			var err error
+			n, err = strconv.Atoi(pflag.Arg(2))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of teams %q", pflag.Arg(2))
+			}
+		}
+		return revealAdvance(ctx, n, false)
+	case "all":
+		return revealAdvance(ctx, 0, true)
+	case "reset":
+		return revealReset(ctx)
+	default:
+		return fmt.Errorf("missing or invalid reveal command %q", pflag.Arg(1))
+	}
+}
+
+// revealAdvance reveals the next n teams of the division, or all remaining
+// teams. The reveal is started if it hasn't been yet.
+func revealAdvance(ctx Context, n int, all bool) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	var order []string
+	var before, after int
+	err = ctx.database.Tx(func(q *db.Queries) error {
+		order, err = server.RevealOrder(ctx, q, division.ID, ctx.config.Leaderboard)
+		if err != nil {
+			return err
+		}
+
+		current, err := q.GetLeaderboardReveal(ctx, division.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get leaderboard reveal: %w", err)
+		}
+		before = min(int(current.Revealed), len(order))
+
+		after = min(before+n, len(order))
+		if all {
+			after = len(order)
+		}
+
+		_, err = q.SetLeaderboardReveal(ctx, db.SetLeaderboardRevealParams{
+			Division:  division.ID,
+			Revealed:  int64(after),
+			UpdatedAt: ctx.now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set leaderboard reveal: %w", err)
+		}
+
+		arguments := map[string]any{"division": division.ID}
+		if n > 0 {
+			arguments["teams"] = n
+		}
+		return ctx.audit(q, "reveal "+pflag.Arg(1), arguments,
+			map[string]any{"revealed": before}, map[string]any{"revealed": after})
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := before; i < after; i++ {
+		fmt.Printf("revealed %d. %s\n", i+1, order[i])
+	}
+	log.Printf("%d of %d teams revealed\n", after, len(order))
+	return nil
+}
+
+func revealReset(ctx Context) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	err = ctx.database.Tx(func(q *db.Queries) error {
+		current, err := q.GetLeaderboardReveal(ctx, division.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get leaderboard reveal: %w", err)
+		}
+
+		if err := q.ResetLeaderboardReveal(ctx, division.ID); err != nil {
+			return fmt.Errorf("failed to reset leaderboard reveal: %w", err)
+		}
+
+		return ctx.audit(q, "reveal reset", map[string]any{
+			"division": division.ID,
+		}, map[string]any{"revealed": current.Revealed}, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Println("reveal reset, the leaderboard is frozen again")
+	return nil
+}
+
+func revealStatus(ctx Context) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	freezeAt := ctx.config.Leaderboard.FreezeAt
+
+	order, err := server.RevealOrder(ctx, ctx.database.Queries, division.ID, ctx.config.Leaderboard)
+	if err != nil {
+		return err
+	}
+
+	current, err := ctx.database.GetLeaderboardReveal(ctx, division.ID)
+	started := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get leaderboard reveal: %w", err)
+	}
+	revealed := min(int(current.Revealed), len(order))
+
+	switch {
+	case !ctx.config.Leaderboard.IsFrozen(ctx.clock.Now()):
+		fmt.Printf("Freezes at %s\n", freezeAt.Local().Format(time.DateTime))
+	case started:
+		fmt.Printf("Frozen since %s, %d of %d teams revealed\n", freezeAt.Local().Format(time.DateTime), revealed, len(order))
+	default:
+		fmt.Printf("Frozen since %s, reveal not started\n", freezeAt.Local().Format(time.DateTime))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Order\tTeam\tRevealed")
+	for i, team := range order {
+		fmt.Fprintf(w, "%d\t%s\t%t\n", i+1, team, i < revealed)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+	"github.com/spf13/pflag"
+)
+
+func schedule(ctx Context) error {
+	switch pflag.Arg(1) {
+	case "list":
+		return scheduleList(ctx)
+	case "status":
+		return scheduleStatus(ctx)
+	case "pause":
+		return scheduleOverride(ctx, problem.PauseReleases, -1, 0, pflag.Arg(2))
+	case "resume":
+		return scheduleOverride(ctx, problem.ResumeReleases, -1, 0, pflag.Arg(2))
+	case "delay":
+		day, err := parseProblemDay(ctx, pflag.Arg(2))
+		if err != nil {
+			return err
+		}
+		delay, err := time.ParseDuration(pflag.Arg(3))
+		if err != nil {
+			return fmt.Errorf("invalid delay: %w", err)
+		}
+		if delay <= 0 {
+			return fmt.Errorf("invalid delay %v, must be positive", delay)
+		}
+		// Delaying a released problem takes it away from the teams that are
+		// working on it, so it has to be asked for explicitly.
+		problems, err := loadScheduledProblemSet(ctx)
+		if err != nil {
+			return err
+		}
+		if day <= problems.AvailableProblems() && !force {
+			return fmt.Errorf("day %d is already released, pass --force to delay it anyway", day)
+		}
+		return scheduleOverride(ctx, problem.DelayRelease, day, delay, pflag.Arg(4))
+	case "release":
+		var day int
+		if pflag.Arg(2) == "" || pflag.Arg(2) == "next" {
+			problems, err := loadScheduledProblemSet(ctx)
+			if err != nil {
+				return err
+			}
+			day = problems.AvailableProblems() + 1
+			if day > problems.TotalProblems() {
+				return fmt.Errorf("all problems are already released")
+			}
+		} else {
+			var err error
+			day, err = parseProblemDay(ctx, pflag.Arg(2))
+			if err != nil {
+				return err
+			}
+		}
+		return scheduleOverride(ctx, problem.ForceRelease, day, 0, pflag.Arg(3))
+	default:
+		return fmt.Errorf("missing or invalid schedule command %q", pflag.Arg(1))
+	}
+}
+
+func parseProblemDay(ctx Context, arg string) (int, error) {
+	division, err := ctx.division()
+	if err != nil {
+		return 0, err
+	}
+	day, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid problem day: %w", err)
+	}
+	if day < 1 || day > len(division.Problems.Modules) {
+		return 0, fmt.Errorf("problem day %d out of range", day)
+	}
+	return day, nil
+}
+
+// scheduleOverride records a schedule override. The day is ignored if it is
+// not positive.
+func scheduleOverride(ctx Context, action problem.ScheduleOverrideAction, day int, delay time.Duration, reason string) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	var o db.ScheduleOverride
+	err = ctx.database.Tx(func(q *db.Queries) error {
+		var err error
+		o, err = q.AddScheduleOverride(ctx, db.AddScheduleOverrideParams{
+			Division: division.ID,
+			Action:   string(action),
+			ProblemIndex: sql.NullInt64{
+				Int64: int64(day - 1),
+				Valid: day > 0,
+			},
+			DelaySeconds: sql.NullInt64{
+				Int64: int64(delay / time.Second),
+				Valid: action == problem.DelayRelease,
+			},
+			Reason:    reason,
+			CreatedAt: ctx.now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add schedule override: %w", err)
+		}
+
+		arguments := map[string]any{
+			"division": division.ID,
+			"reason":   reason,
+		}
+		if day > 0 {
+			arguments["day"] = day
+		}
+		if action == problem.DelayRelease {
+			arguments["delay"] = delay.String()
+		}
+
+		return ctx.audit(q, "schedule "+string(action), arguments, nil, map[string]any{
+			"override_id": o.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("recorded schedule override #%d (%s)\n", o.ID, describeScheduleOverride(o))
+	return nil
+}
+
+func describeScheduleOverride(o db.ScheduleOverride) string {
+	switch problem.ScheduleOverrideAction(o.Action) {
+	case problem.DelayRelease:
+		delay := time.Duration(o.DelaySeconds.Int64) * time.Second
+		return fmt.Sprintf("delay day %d by %v", o.ProblemIndex.Int64+1, delay)
+	case problem.ForceRelease:
+		return fmt.Sprintf("release day %d", o.ProblemIndex.Int64+1)
+	default:
+		return o.Action
+	}
+}
+
+// loadScheduledProblemSet loads the problem set of the selected division with
+// its schedule overrides applied. The problems only carry their IDs and
+// configuration, they can't be run.
+func loadScheduledProblemSet(ctx Context) (*problem.ProblemSet, error) {
+	division, err := ctx.division()
+	if err != nil {
+		return nil, err
+	}
+	return newScheduledProblemSet(ctx, division)
+}
+
+// newScheduledProblemSet creates the problem set of the given division config
+// with the schedule overrides of the division applied.
+func newScheduledProblemSet(ctx Context, division *config.DivisionConfig) (*problem.ProblemSet, error) {
+	schedule := division.Problems.Schedule.ReleaseSchedule()
+	problems := make([]problem.Problem, len(division.Problems.Modules))
+	for i, module := range division.Problems.Modules {
+		problems[i] = problem.NewProblem(module.ProblemID(), problem.ProblemDescription{}, nil, module.ProblemConfig)
+	}
+	problemset := problem.NewProblemSetWithSchedule(problems, schedule)
+	problemset.SetClock(ctx.clock)
+
+	rows, err := ctx.database.ListScheduleOverrides(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+
+	overrides := make([]problem.ScheduleOverride, 0, len(rows))
+	for _, row := range rows {
+		if row.Division == division.ID {
+			overrides = append(overrides, server.ConvertScheduleOverride(row))
+		}
+	}
+
+	if err := problemset.SetScheduleOverrides(overrides); err != nil {
+		return nil, fmt.Errorf("failed to apply schedule overrides: %w", err)
+	}
+
+	return problemset, nil
+}
+
+func scheduleList(ctx Context) error {
+	overrides, err := ctx.database.ListScheduleOverrides(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tCreated At\tDivision\tOverride\tReason\n")
+	fmt.Fprintf(w, "--\t----------\t--------\t--------\t------\n")
+
+	for _, o := range overrides {
+		if !inDivision(o.Division) {
+			continue
+		}
+		fmt.Fprintf(w,
+			"%d\t%v\t%s\t%s\t%s\n",
+			o.ID, o.CreatedAt.Time().In(time.Local), o.Division, describeScheduleOverride(o), o.Reason)
+	}
+
+	w.Flush()
+	fmt.Print(b.String())
+
+	return nil
+}
+
+func scheduleStatus(ctx Context) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	problems, err := loadScheduledProblemSet(ctx)
+	if err != nil {
+		return err
+	}
+
+	available := problems.AvailableProblems()
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Day\tModule\tRelease\tStatus\n")
+	fmt.Fprintf(w, "---\t------\t-------\t------\n")
+
+	for i, module := range division.Problems.Modules {
+		status := "released"
+		release := problems.ProblemStartTime(i).In(time.Local).String()
+		if i >= available {
+			status = "pending"
+			if problems.IsPaused() && problems.NextReleaseTime().IsZero() {
+				status = "paused"
+				release = "-"
+			}
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, module.README, release, status)
+	}
+
+	w.Flush()
+	fmt.Print(b.String())
+
+	return nil
+}
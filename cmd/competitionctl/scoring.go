@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+	"github.com/spf13/pflag"
+)
+
+// defaultSimulateOffsets are the times after a problem's release that scoring
+// simulate prints the points at by default.
+var defaultSimulateOffsets = []time.Duration{
+	0,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	2 * time.Hour,
+	4 * time.Hour,
+	8 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	48 * time.Hour,
+}
+
+// simulateSolveCounts are the solve counts that scoring simulate prints the
+// points of solve count scaling at.
+var simulateSolveCounts = []int{1, 2, 3, 4, 6, 8, 12, 16, 20}
+
+func scoring(ctx Context) error {
+	switch pflag.Arg(1) {
+	case "simulate":
+		if replay != 0 {
+			return scoringReplay(ctx, problem.ScoringVersion(replay))
+		}
+		return scoringSimulate(ctx)
+	default:
+		return fmt.Errorf("missing or invalid scoring command %q", pflag.Arg(1))
+	}
+}
+
+// scoringVersions returns every valid scoring version.
+func scoringVersions() []problem.ScoringVersion {
+	var versions []problem.ScoringVersion
+	for v := problem.ScoringVersion(1); v.IsValid(); v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// pointsPerPartValues returns the distinct points per part of every problem
+// in the config, along with the default.
+func pointsPerPartValues(ctx Context) []float64 {
+	values := []float64{problem.PointsPerPart}
+	for _, division := range ctx.config.AllDivisions() {
+		for _, module := range division.Problems.Modules {
+			if module.PointsPerPart != 0 && !slices.Contains(values, module.PointsPerPart) {
+				values = append(values, module.PointsPerPart)
+			}
+		}
+	}
+	slices.Sort(values)
+	return values
+}
+
+// curveModules returns the problems in the config that define their own
+// scoring curve.
+func curveModules(ctx Context) []problem.ModuleConfig {
+	var modules []problem.ModuleConfig
+	for _, division := range ctx.config.AllDivisions() {
+		for _, module := range division.Problems.Modules {
+			if module.ScoringCurve != nil {
+				modules = append(modules, module)
+			}
+		}
+	}
+	return modules
+}
+
+func scoringSimulate(ctx Context) error {
+	offsets := defaultSimulateOffsets
+	if pflag.NArg() > 2 {
+		offsets = make([]time.Duration, 0, pflag.NArg()-2)
+		for _, arg := range pflag.Args()[2:] {
+			offset, err := time.ParseDuration(arg)
+			if err != nil {
+				return fmt.Errorf("invalid offset %q: %w", arg, err)
+			}
+			offsets = append(offsets, offset)
+		}
+	}
+
+	var timed, solveCount []problem.ScoringVersion
+	for _, version := range scoringVersions() {
+		if version.IsSolveCountScaling() {
+			solveCount = append(solveCount, version)
+		} else {
+			timed = append(timed, version)
+		}
+	}
+
+	pointsPerPart := pointsPerPartValues(ctx)
+	curves := curveModules(ctx)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprint(w, "Offset\t")
+	for _, version := range timed {
+		for _, points := range pointsPerPart {
+			fmt.Fprintf(w, "v%d (%.0f)\t", version, points)
+		}
+	}
+	for _, module := range curves {
+		fmt.Fprintf(w, "%s (%s)\t", module.ProblemID(), module.ScoringCurve.Type)
+	}
+	fmt.Fprintln(w)
+
+	var releasedAt time.Time
+	for _, offset := range offsets {
+		fmt.Fprintf(w, "%v\t", offset)
+		for _, version := range timed {
+			for _, points := range pointsPerPart {
+				fmt.Fprintf(w, "%.2f\t", problem.ScalePoints(releasedAt.Add(offset), releasedAt, points, version))
+			}
+		}
+		for _, module := range curves {
+			fmt.Fprintf(w, "%.2f\t", module.ScalePoints(releasedAt.Add(offset), releasedAt))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(solveCount) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprint(w, "Solves\t")
+		for _, version := range solveCount {
+			for _, points := range pointsPerPart {
+				fmt.Fprintf(w, "v%d (%.0f)\t", version, points)
+			}
+		}
+		fmt.Fprintln(w)
+
+		for _, solves := range simulateSolveCounts {
+			fmt.Fprintf(w, "%d\t", solves)
+			for range solveCount {
+				for _, points := range pointsPerPart {
+					fmt.Fprintf(w, "%.2f\t", problem.SolveCountPoints(solves, points))
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	return w.Flush()
+}
+
+// scoringReplay replays the submission history of the division as if every
+// problem used the given scoring version, and compares the resulting
+// standings to the current ones. Nothing is modified.
+func scoringReplay(ctx Context, version problem.ScoringVersion) error {
+	if !version.IsValid() {
+		return fmt.Errorf("invalid scoring version %d", version)
+	}
+
+	divisionConfig, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	replayed := *divisionConfig
+	replayed.Problems.Modules = slices.Clone(divisionConfig.Problems.Modules)
+	for i := range replayed.Problems.Modules {
+		module := &replayed.Problems.Modules[i]
+		module.ScoringVersion = version
+		module.ScoringCurve = nil
+		if err := module.ProblemConfig.Validate(); err != nil {
+			return fmt.Errorf("problem %q cannot use scoring version %d: %w", module.ProblemID(), version, err)
+		}
+	}
+
+	problems, err := newScheduledProblemSet(ctx, &replayed)
+	if err != nil {
+		return err
+	}
+
+	division := &server.Division{
+		ID:       divisionConfig.ID,
+		Name:     divisionConfig.Name,
+		Problems: problems,
+	}
+
+	adjustments, err := server.Rescore(ctx, ctx.database.Queries, division)
+	if err != nil {
+		return fmt.Errorf("failed to rescore: %w", err)
+	}
+
+	tiebreakers := ctx.config.Leaderboard.TiebreakRules()
+
+	current, err := server.Standings(ctx, ctx.database.Queries, division.ID, tiebreakers)
+	if err != nil {
+		return fmt.Errorf("failed to get standings: %w", err)
+	}
+
+	deltas := make(map[string]float64)
+	for _, adjustment := range adjustments {
+		deltas[adjustment.TeamName] += adjustment.Delta()
+	}
+
+	replay := slices.Clone(current)
+	for i := range replay {
+		replay[i].Points += deltas[replay[i].TeamName]
+	}
+	server.RankStandings(replay, tiebreakers)
+
+	currentRanks := make(map[string]server.TeamStanding, len(current))
+	for _, standing := range current {
+		currentRanks[standing.TeamName] = standing
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Rank\tTeam\tPoints\tCurrent Rank\tCurrent Points\tChange\n")
+	fmt.Fprintf(w, "----\t----\t------\t------------\t--------------\t------\n")
+	for _, standing := range replay {
+		before := currentRanks[standing.TeamName]
+
+		change := "="
+		switch places := before.Rank - standing.Rank; {
+		case places > 0:
+			change = fmt.Sprintf("+%d", places)
+		case places < 0:
+			change = fmt.Sprintf("%d", places)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%.2f\t%d\t%.2f\t%s\n",
+			standing.Rank, standing.TeamName, standing.Points, before.Rank, before.Points, change)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	log.Printf("replayed %d solve and penalty adjustments under scoring version %d\n", len(adjustments), version)
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/spf13/pflag"
+)
+
+// voidProblem voids parts of a problem. The credit is only given to the teams
+// that exist in the division when the problem is voided; teams created later
+// don't get it.
+func voidProblem(ctx Context) error {
+	division, err := ctx.division()
+	if err != nil {
+		return err
+	}
+
+	day, err := parseProblemDay(ctx, pflag.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	var parts []int
+	switch pflag.Arg(2) {
+	case "1":
+		parts = []int{1}
+	case "2":
+		parts = []int{2}
+	case "all":
+		parts = []int{1, 2}
+	default:
+		return fmt.Errorf("invalid part %q, must be 1, 2 or all", pflag.Arg(2))
+	}
+
+	var credit float64
+	if pflag.Arg(3) != "" {
+		credit, err = strconv.ParseFloat(pflag.Arg(3), 64)
+		if err != nil {
+			return fmt.Errorf("invalid credit: %w", err)
+		}
+		if credit < 0 {
+			return fmt.Errorf("credit must not be negative")
+		}
+	}
+
+	reason := pflag.Arg(4)
+	module := division.Problems.Modules[day-1]
+
+	return ctx.database.Tx(func(q *db.Queries) error {
+		teams, err := q.ListTeams(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list teams: %w", err)
+		}
+
+		// The audit log records the points that each team had for each part.
+		before := make(map[string]map[string]float64, len(parts))
+		var voided []string
+
+		for _, part := range parts {
+			problemID := fmt.Sprintf("%s/part%d", module.ProblemID(), part)
+
+			_, err := q.VoidProblem(ctx, db.VoidProblemParams{
+				Division:  division.ID,
+				ProblemID: problemID,
+				Credit:    credit,
+				Reason:    reason,
+				VoidedAt:  ctx.now(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to void %q (already voided?): %w", problemID, err)
+			}
+
+			earned, err := q.ProblemPointsByTeam(ctx, db.ProblemPointsByTeamParams{
+				ProblemID: sql.NullString{String: problemID, Valid: true},
+				Division:  division.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get points for %q: %w", problemID, err)
+			}
+
+			earnedByTeam := make(map[string]float64, len(earned))
+			for _, row := range earned {
+				earnedByTeam[row.TeamName] += row.Points.Float64
+			}
+			before[problemID] = earnedByTeam
+			voided = append(voided, problemID)
+
+			var credited int
+			for _, team := range teams {
+				if team.Division != division.ID {
+					continue
+				}
+
+				// Cancel out the points instead of deleting them, so that the
+				// leaderboard history stays intact and the void is traceable.
+				if points := earnedByTeam[team.TeamName]; points != 0 {
+					if err := addVoidedPoints(ctx, q, team.TeamName, -points, problemID, part); err != nil {
+						return err
+					}
+				}
+
+				if credit > 0 {
+					if err := addVoidedPoints(ctx, q, team.TeamName, credit, problemID, part); err != nil {
+						return err
+					}
+					credited++
+				}
+			}
+
+			log.Printf("voided day %d part %d (%s), removed points from %d teams\n",
+				day, part, problemID, len(earnedByTeam))
+			if credit > 0 {
+				log.Printf("credited %v points to the %d current teams, teams created later are not credited\n",
+					credit, credited)
+			}
+		}
+
+		return ctx.audit(q, "void", map[string]any{
+			"division": division.ID,
+			"day":      day,
+			"part":     pflag.Arg(2),
+			"credit":   credit,
+			"reason":   reason,
+		}, before, map[string]any{
+			"voided": voided,
+			"credit": credit,
+		})
+	})
+}
+
+// addVoidedPoints adds a ledger entry for a voided problem part, both for
+// removing points and for crediting them.
+func addVoidedPoints(ctx Context, q *db.Queries, team string, points float64, problemID string, part int) error {
+	_, err := q.AddPoints(ctx, db.AddPointsParams{
+		TeamName:  team,
+		Points:    points,
+		Reason:    server.PointsFromVoid.Label(),
+		Source:    string(server.PointsFromVoid),
+		ProblemID: sql.NullString{String: problemID, Valid: true},
+		Part:      sql.NullInt64{Int64: int64(part), Valid: true},
+		Actor:     ctx.actor(),
+		AddedAt:   ctx.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add voided points for team %q: %w", team, err)
+	}
+	return nil
+}
+
+func listVoided(ctx Context) error {
+	voided, err := ctx.database.ListVoidedProblems(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list voided problems: %w", err)
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Voided At\tDivision\tProblem\tCredit\tReason\n")
+	fmt.Fprintf(w, "---------\t--------\t-------\t------\t------\n")
+
+	for _, v := range voided {
+		if !inDivision(v.Division) {
+			continue
+		}
+		fmt.Fprintf(w,
+			"%v\t%s\t%s\t%.0f\t%s\n",
+			v.VoidedAt.Time().In(time.Local), v.Division, v.ProblemID, v.Credit, v.Reason)
+	}
+
+	w.Flush()
+	fmt.Print(b.String())
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package clock provides the clock used to tell the competition time. It can
+// be replaced with a simulated clock to rehearse the competition.
+package clock
+
+import "time"
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock that tells the real time.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Simulated is a clock that starts at a given time and optionally runs faster
+// than real time.
+type Simulated struct {
+	start  time.Time
+	anchor time.Time
+	speed  float64
+}
+
+var _ Clock = (*Simulated)(nil)
+
+// NewSimulated creates a new simulated clock that tells the time start at the
+// real time anchor. From then on, the simulated time advances speed times as
+// fast as the real time. A speed of 0 is treated as 1.
+func NewSimulated(start, anchor time.Time, speed float64) *Simulated {
+	if speed == 0 {
+		speed = 1
+	}
+	return &Simulated{
+		start:  start,
+		anchor: anchor,
+		speed:  speed,
+	}
+}
+
+// Now implements Clock.
+func (c *Simulated) Now() time.Time {
+	elapsed := time.Since(c.anchor)
+	return c.start.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// Speed returns how many times faster than real time the clock runs.
+func (c *Simulated) Speed() float64 {
+	return c.speed
+}
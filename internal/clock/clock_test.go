@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSimulated(t *testing.T) {
+	start := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	// The clock was anchored a minute ago, and some more real time passes
+	// while the test runs.
+	const slack = 5 * time.Second
+
+	tests := []struct {
+		name    string
+		speed   float64
+		elapsed time.Duration
+	}{
+		{"faster", 60, time.Hour},
+		{"half", 0.5, 30 * time.Second},
+		{"zero is real time", 0, time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewSimulated(start, time.Now().Add(-time.Minute), test.speed)
+
+			elapsed := c.Now().Sub(start)
+			assert.True(t, elapsed >= test.elapsed, "elapsed %v, want %v", elapsed, test.elapsed)
+			assert.True(t, elapsed < test.elapsed+time.Duration(float64(slack)*max(c.Speed(), 1)),
+				"elapsed %v, want %v", elapsed, test.elapsed)
+		})
+	}
+
+	assert.Equal(t, 1.0, NewSimulated(start, start, 0).Speed())
+}
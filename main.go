@@ -8,7 +8,11 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"dev.acmcsuf.com/march-madness-2024/internal/clock"
 	"dev.acmcsuf.com/march-madness-2024/internal/config"
 	"dev.acmcsuf.com/march-madness-2024/server"
 	"dev.acmcsuf.com/march-madness-2024/server/db"
@@ -22,11 +26,15 @@ import (
 var (
 	configPath = "config.json"
 	verbose    = false
+	clockStart = ""
+	clockSpeed = 1.0
 )
 
 func main() {
 	pflag.StringVarP(&configPath, "config", "c", configPath, "path to config file")
 	pflag.BoolVarP(&verbose, "verbose", "v", verbose, "enable verbose logging")
+	pflag.StringVar(&clockStart, "clock-start", clockStart, "start the server as if it were this RFC 3339 time (overrides config)")
+	pflag.Float64Var(&clockSpeed, "clock-speed", clockSpeed, "speed of the simulated clock started with --clock-start")
 	pflag.Parse()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -50,51 +58,115 @@ func run(ctx context.Context) error {
 	logger := slog.New(logOutput)
 	slog.SetDefault(logger)
 
-	config, err := config.ParseFile(configPath)
+	cfg, err := config.ParseFile(configPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	frontendDir := os.DirFS(config.Paths.Frontend)
-	logger.Debug("using frontend dir", "path", config.Paths.Frontend)
+	if clockStart != "" {
+		start, err := time.Parse(time.RFC3339, clockStart)
+		if err != nil {
+			return fmt.Errorf("invalid --clock-start: %w", err)
+		}
+		cfg.Clock = config.ClockConfig{
+			Start: start,
+			Speed: clockSpeed,
+		}
+	}
+
+	clock := cfg.Clock.Clock()
+	if cfg.Clock.IsSimulated() {
+		logger.Warn("using simulated clock",
+			"now", clock.Now(),
+			"speed", cfg.Clock.Speed)
+	}
+
+	frontendDir := os.DirFS(cfg.Paths.Frontend)
+	logger.Debug("using frontend dir", "path", cfg.Paths.Frontend)
 
-	database, err := db.Open(config.Paths.Database)
+	database, err := db.Open(cfg.DatabaseURL())
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer database.Close()
 
-	secretKey, err := ensureSecretKey(config.Paths.SecretKey)
+	secretKey, err := ensureSecretKey(cfg.Paths.SecretKey)
 	if err != nil {
 		return fmt.Errorf("failed to ensure secret key exists: %w", err)
 	}
 
-	problems := make([]problem.Problem, len(config.Problems.Modules))
-	for i, module := range config.Problems.Modules {
-		p, err := problem.NewProblemFromModule(module, logger)
+	reloadable, err := newReloadableConfig(cfg, nil, clock, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := server.CheckProblemIDs(ctx, database, reloadable.Divisions); err != nil {
+		return err
+	}
+
+	var srv *server.Server
+	// reloadMu serializes SIGHUP and admin reloads, so that each one builds on
+	// the divisions that the previous one swapped in.
+	var reloadMu sync.Mutex
+	reload := func(ctx context.Context) error {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+
+		newCfg, err := config.ParseFile(configPath)
 		if err != nil {
-			return fmt.Errorf("failed to create problem from module %q: %w", module.README, err)
+			return fmt.Errorf("failed to parse config: %w", err)
 		}
-		problems[i] = p
+
+		if newCfg.HTTPAddress != cfg.HTTPAddress || newCfg.Paths != cfg.Paths ||
+			newCfg.Database != cfg.Database || newCfg.Backups != cfg.Backups {
+			logger.Warn("changes to http_address, paths, database and backups require a restart")
+		}
+
+		reloadable, err := newReloadableConfig(newCfg, srv.Divisions(), clock, logger)
+		if err != nil {
+			return err
+		}
+
+		return srv.Reload(ctx, reloadable)
 	}
-	problem.CacheAllProblems(problems, logger.With("component", "problem_cache"))
 
-	problemset := problem.NewProblemSetWithSchedule(problems, &problem.ProblemReleaseSchedule{
-		StartReleaseAt: config.Problems.Schedule.Start,
-		ReleaseEvery:   config.Problems.Schedule.Every.Duration(),
+	srv = server.New(server.ServerConfig{
+		FrontendDir:      frontendDir,
+		SecretKey:        secretKey,
+		Database:         database,
+		Logger:           logger.With("component", "http"),
+		Clock:            clock,
+		Reload:           reload,
+		ReloadableConfig: reloadable,
 	})
 
-	server := server.New(server.ServerConfig{
-		FrontendDir:          frontendDir,
-		SecretKey:            secretKey,
-		Problems:             problemset,
-		Database:             database,
-		Logger:               logger.With("component", "http"),
-		HackathonConfig:      config.Hackathon,
-		OpenRegistrationTime: config.OpenRegistrationTime,
-	})
+	if err := srv.SyncScheduleOverrides(ctx); err != nil {
+		return fmt.Errorf("failed to sync schedule overrides: %w", err)
+	}
+
+	if err := srv.ScheduleBackups(ctx, cfg.Backups); err != nil {
+		return fmt.Errorf("failed to schedule backups: %w", err)
+	}
 
-	handler := http.Handler(server)
+	go func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		defer signal.Stop(sighup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				logger.Info("received SIGHUP, reloading configuration")
+				if err := reload(ctx); err != nil {
+					logger.Error("failed to reload configuration", "err", err)
+				}
+			}
+		}
+	}()
+
+	handler := http.Handler(srv)
 	if verbose {
 		httpLogger := &httplog.Logger{
 			Logger:  logger,
@@ -104,8 +176,69 @@ func run(ctx context.Context) error {
 		handler = middleware(handler)
 	}
 
-	logger.Info("starting server", "addr", config.HTTPAddress)
-	return hserve.ListenAndServe(ctx, config.HTTPAddress, handler)
+	logger.Info("starting server", "addr", cfg.HTTPAddress)
+	return hserve.ListenAndServe(ctx, cfg.HTTPAddress, handler)
+}
+
+// newReloadableConfig creates the parts of the server configuration that can
+// be reloaded. Problems are reused from the previous divisions if their input
+// generators did not change, so that their caches are kept. The clock is not
+// reloadable.
+func newReloadableConfig(cfg *config.Config, previous []server.Division, clock clock.Clock, logger *slog.Logger) (server.ReloadableConfig, error) {
+	divisions := make([]server.Division, 0, len(cfg.AllDivisions()))
+	for _, division := range cfg.AllDivisions() {
+		var previousProblems []problem.Problem
+		for _, d := range previous {
+			if d.ID == division.ID {
+				previousProblems = d.Problems.AllProblems()
+			}
+		}
+
+		problemset, err := newProblemSet(division.Problems, previousProblems, clock, logger)
+		if err != nil {
+			return server.ReloadableConfig{}, fmt.Errorf("failed to create problems for division %q: %w", division.ID, err)
+		}
+
+		divisions = append(divisions, server.Division{
+			ID:       division.ID,
+			Name:     division.Name,
+			Problems: problemset,
+		})
+	}
+
+	return server.ReloadableConfig{
+		Divisions:            divisions,
+		HackathonConfig:      cfg.Hackathon,
+		OpenRegistrationTime: cfg.OpenRegistrationTime,
+		AdminToken:           cfg.AdminToken,
+		Leaderboard:          cfg.Leaderboard,
+	}, nil
+}
+
+func newProblemSet(cfg config.ProblemsConfig, previous []problem.Problem, clock clock.Clock, logger *slog.Logger) (*problem.ProblemSet, error) {
+	problems := make([]problem.Problem, len(cfg.Modules))
+	for i, module := range cfg.Modules {
+		if module.FirstSolveBonuses == nil {
+			module.FirstSolveBonuses = cfg.FirstSolveBonuses
+		}
+		module.Cooldown = module.Cooldown.Or(cfg.Cooldown)
+		p, err := problem.NewProblemFromModule(module, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create problem from module %q: %w", module.README, err)
+		}
+		problems[i] = p
+	}
+	problem.CacheProblems(problems, previous, logger.With("component", "problem_cache"))
+
+	schedule := cfg.Schedule.ReleaseSchedule()
+	if err := schedule.Validate(len(problems)); err != nil {
+		return nil, fmt.Errorf("invalid problem schedule: %w", err)
+	}
+
+	problemset := problem.NewProblemSetWithSchedule(problems, schedule)
+	problemset.SetClock(clock)
+
+	return problemset, nil
 }
 
 func ensureSecretKey(path string) (server.SecretKey, error) {
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) routeAdmin(r chi.Router) {
+	r.Use(s.requireAdmin)
+	r.Post("/reload", s.adminReload)
+}
+
+// requireAdmin only allows requests carrying the admin token in the
+// Authorization header. All admin endpoints are hidden if no admin token is
+// configured.
+func (s *Server) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adminToken := s.live.Load().AdminToken
+		if adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) adminReload(w http.ResponseWriter, r *http.Request) {
+	if s.config.Reload == nil {
+		writeError(w, http.StatusNotImplemented, errors.New("reloading is not supported"))
+		return
+	}
+
+	if err := s.config.Reload(r.Context()); err != nil {
+		s.logger.ErrorContext(r.Context(),
+			"admin reload failed",
+			"err", err)
+
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if err := RecordAudit(r.Context(), s.database.Queries, AuditEntry{
+		Actor:  adminActor(r),
+		Action: "reload",
+	}, s.clock.Now()); err != nil {
+		s.logger.ErrorContext(r.Context(),
+			"failed to record admin reload in the audit log",
+			"err", err)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("reloaded\n"))
+}
@@ -0,0 +1,738 @@
+// Package archive exports a whole competition to an archive and imports it
+// back into an empty database.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+)
+
+// FormatVersion is the version of the archive format. It is incremented
+// whenever the format changes in a way that older versions can't import.
+const FormatVersion = 1
+
+// archiveFile is the name of the JSON file inside zip archives.
+const archiveFile = "competition.json"
+
+// Archive is everything stored about a competition. Rows keep their original
+// IDs and timestamps, so that importing an archive rebuilds the database as
+// it was.
+type Archive struct {
+	FormatVersion int       `json:"format_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	// Divisions describe the problems and final standings of each division
+	// at the time of the export. They are informational and aren't imported,
+	// since problems are configured rather than stored.
+	Divisions          []Division          `json:"divisions"`
+	Teams              []Team              `json:"teams"`
+	Submissions        []Submission        `json:"submissions"`
+	Points             []Points            `json:"points"`
+	ScheduleOverrides  []ScheduleOverride  `json:"schedule_overrides"`
+	VoidedProblems     []VoidedProblem     `json:"voided_problems"`
+	FirstSolves        []FirstSolve        `json:"first_solves"`
+	ProblemStarts      []ProblemStart      `json:"problem_starts"`
+	LeaderboardReveals []LeaderboardReveal `json:"leaderboard_reveals"`
+	AuditLog           []AuditLogEntry     `json:"audit_log"`
+
+	// Files are other files stored in a zip archive, such as the READMEs of
+	// the problems, by their path in the archive.
+	Files map[string][]byte `json:"-"`
+}
+
+// Division describes a division of the competition.
+type Division struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Problems  []Problem  `json:"problems"`
+	Standings []Standing `json:"standings"`
+}
+
+// Problem describes a problem of a division.
+type Problem struct {
+	ID         string    `json:"id"`
+	ReleasedAt time.Time `json:"released_at"`
+	// README is the path of the problem's README in the archive, if it was
+	// stored.
+	README string               `json:"readme,omitempty"`
+	Module problem.ModuleConfig `json:"module"`
+}
+
+// READMEPath returns the path in the archive that the README of a problem is
+// stored at.
+func READMEPath(divisionID, problemID string) string {
+	return filepath.ToSlash(filepath.Join("problems", divisionID, problemID, "README.md"))
+}
+
+// Standing is the rank and points of a team.
+type Standing struct {
+	Rank     int     `json:"rank"`
+	TeamName string  `json:"team_name"`
+	Points   float64 `json:"points"`
+}
+
+type Team struct {
+	Name                string               `json:"name"`
+	Division            string               `json:"division"`
+	InviteCode          string               `json:"invite_code"`
+	AcceptingMembers    bool                 `json:"accepting_members"`
+	CreatedAt           time.Time            `json:"created_at"`
+	Members             []Member             `json:"members"`
+	HackathonSubmission *HackathonSubmission `json:"hackathon_submission,omitempty"`
+}
+
+type Member struct {
+	Username string    `json:"username"`
+	IsLeader bool      `json:"is_leader"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type HackathonSubmission struct {
+	ProjectURL         string    `json:"project_url"`
+	ProjectDescription *string   `json:"project_description,omitempty"`
+	Category           string    `json:"category"`
+	WonRank            *int64    `json:"won_rank,omitempty"`
+	SubmittedAt        time.Time `json:"submitted_at"`
+}
+
+type Submission struct {
+	ID          int64     `json:"id"`
+	TeamName    string    `json:"team_name"`
+	ProblemID   string    `json:"problem_id"`
+	SubmittedBy *string   `json:"submitted_by,omitempty"`
+	Correct     bool      `json:"correct"`
+	Practice    bool      `json:"practice"`
+	Answer      *int64    `json:"answer,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type Points struct {
+	ID           int64     `json:"id"`
+	TeamName     string    `json:"team_name"`
+	Points       float64   `json:"points"`
+	Reason       string    `json:"reason"`
+	Source       string    `json:"source"`
+	ProblemID    *string   `json:"problem_id,omitempty"`
+	Part         *int64    `json:"part,omitempty"`
+	SubmissionID *int64    `json:"submission_id,omitempty"`
+	Actor        *string   `json:"actor,omitempty"`
+	AddedAt      time.Time `json:"added_at"`
+}
+
+type ScheduleOverride struct {
+	ID           int64     `json:"id"`
+	Division     string    `json:"division"`
+	Action       string    `json:"action"`
+	ProblemIndex *int64    `json:"problem_index,omitempty"`
+	DelaySeconds *int64    `json:"delay_seconds,omitempty"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type VoidedProblem struct {
+	Division  string    `json:"division"`
+	ProblemID string    `json:"problem_id"`
+	Credit    float64   `json:"credit"`
+	Reason    string    `json:"reason"`
+	VoidedAt  time.Time `json:"voided_at"`
+}
+
+type FirstSolve struct {
+	Division  string    `json:"division"`
+	ProblemID string    `json:"problem_id"`
+	Rank      int64     `json:"rank"`
+	TeamName  string    `json:"team_name"`
+	Bonus     float64   `json:"bonus"`
+	SolvedAt  time.Time `json:"solved_at"`
+}
+
+type ProblemStart struct {
+	TeamName  string    `json:"team_name"`
+	ProblemID string    `json:"problem_id"`
+	StartedBy string    `json:"started_by"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type LeaderboardReveal struct {
+	Division  string    `json:"division"`
+	Revealed  int64     `json:"revealed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AuditLogEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Arguments json.RawMessage `json:"arguments"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Export exports the database into a new archive. The divisions of the
+// archive are left for the caller to fill in.
+func Export(ctx context.Context, q *db.Queries, now time.Time) (*Archive, error) {
+	a := &Archive{
+		FormatVersion: FormatVersion,
+		ExportedAt:    now.UTC(),
+		Files:         make(map[string][]byte),
+	}
+
+	teams, err := q.ListAllTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	members, err := q.ListAllTeamMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+
+	hackathon, err := q.HackathonSubmissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hackathon submissions: %w", err)
+	}
+
+	a.Teams = make([]Team, len(teams))
+	for i, team := range teams {
+		a.Teams[i] = Team{
+			Name:             team.TeamName,
+			Division:         team.Division,
+			InviteCode:       team.InviteCode,
+			AcceptingMembers: team.AcceptingMembers,
+			CreatedAt:        team.CreatedAt.Time(),
+			Members:          []Member{},
+		}
+		for _, member := range members {
+			if member.TeamName == team.TeamName {
+				a.Teams[i].Members = append(a.Teams[i].Members, Member{
+					Username: member.Username,
+					IsLeader: member.IsLeader,
+					JoinedAt: member.JoinedAt.Time(),
+				})
+			}
+		}
+		for _, submission := range hackathon {
+			if submission.TeamName == team.TeamName {
+				a.Teams[i].HackathonSubmission = &HackathonSubmission{
+					ProjectURL:         submission.ProjectUrl,
+					ProjectDescription: stringPtr(submission.ProjectDescription),
+					Category:           submission.Category,
+					WonRank:            int64Ptr(submission.WonRank),
+					SubmittedAt:        submission.SubmittedAt.Time(),
+				}
+			}
+		}
+	}
+
+	submissions, err := q.ListSubmitAttempts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submissions: %w", err)
+	}
+	a.Submissions = make([]Submission, len(submissions))
+	for i, submission := range submissions {
+		a.Submissions[i] = Submission{
+			ID:          submission.ID,
+			TeamName:    submission.TeamName,
+			ProblemID:   submission.ProblemID,
+			SubmittedBy: stringPtr(submission.SubmittedBy),
+			Correct:     submission.Correct,
+			Practice:    submission.Practice,
+			Answer:      int64Ptr(submission.Answer),
+			SubmittedAt: submission.SubmittedAt.Time(),
+		}
+	}
+
+	points, err := q.ListPoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list points: %w", err)
+	}
+	a.Points = make([]Points, len(points))
+	for i, p := range points {
+		a.Points[i] = Points{
+			ID:           p.ID,
+			TeamName:     p.TeamName,
+			Points:       p.Points,
+			Reason:       p.Reason,
+			Source:       p.Source,
+			ProblemID:    stringPtr(p.ProblemID),
+			Part:         int64Ptr(p.Part),
+			SubmissionID: int64Ptr(p.SubmissionID),
+			Actor:        stringPtr(p.Actor),
+			AddedAt:      p.AddedAt.Time(),
+		}
+	}
+
+	overrides, err := q.ListScheduleOverrides(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+	a.ScheduleOverrides = make([]ScheduleOverride, len(overrides))
+	for i, override := range overrides {
+		a.ScheduleOverrides[i] = ScheduleOverride{
+			ID:           override.ID,
+			Division:     override.Division,
+			Action:       override.Action,
+			ProblemIndex: int64Ptr(override.ProblemIndex),
+			DelaySeconds: int64Ptr(override.DelaySeconds),
+			Reason:       override.Reason,
+			CreatedAt:    override.CreatedAt.Time(),
+		}
+	}
+
+	voided, err := q.ListVoidedProblems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voided problems: %w", err)
+	}
+	a.VoidedProblems = make([]VoidedProblem, len(voided))
+	for i, v := range voided {
+		a.VoidedProblems[i] = VoidedProblem{
+			Division:  v.Division,
+			ProblemID: v.ProblemID,
+			Credit:    v.Credit,
+			Reason:    v.Reason,
+			VoidedAt:  v.VoidedAt.Time(),
+		}
+	}
+
+	firstSolves, err := q.ListFirstSolves(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list first solves: %w", err)
+	}
+	a.FirstSolves = make([]FirstSolve, len(firstSolves))
+	for i, solve := range firstSolves {
+		a.FirstSolves[i] = FirstSolve{
+			Division:  solve.Division,
+			ProblemID: solve.ProblemID,
+			Rank:      solve.Rank,
+			TeamName:  solve.TeamName,
+			Bonus:     solve.Bonus,
+			SolvedAt:  solve.SolvedAt.Time(),
+		}
+	}
+
+	starts, err := q.ListProblemStarts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problem starts: %w", err)
+	}
+	a.ProblemStarts = make([]ProblemStart, len(starts))
+	for i, start := range starts {
+		a.ProblemStarts[i] = ProblemStart{
+			TeamName:  start.TeamName,
+			ProblemID: start.ProblemID,
+			StartedBy: start.StartedBy,
+			StartedAt: start.StartedAt.Time(),
+		}
+	}
+
+	reveals, err := q.ListLeaderboardReveals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard reveals: %w", err)
+	}
+	a.LeaderboardReveals = make([]LeaderboardReveal, len(reveals))
+	for i, reveal := range reveals {
+		a.LeaderboardReveals[i] = LeaderboardReveal{
+			Division:  reveal.Division,
+			Revealed:  reveal.Revealed,
+			UpdatedAt: reveal.UpdatedAt.Time(),
+		}
+	}
+
+	auditLog, err := q.ListAuditLog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	a.AuditLog = make([]AuditLogEntry, len(auditLog))
+	for i, entry := range auditLog {
+		a.AuditLog[i] = AuditLogEntry{
+			ID:        entry.ID,
+			Actor:     entry.Actor,
+			Action:    entry.Action,
+			Arguments: json.RawMessage(entry.Arguments),
+			Before:    rawJSON(entry.Before),
+			After:     rawJSON(entry.After),
+			CreatedAt: entry.CreatedAt.Time(),
+		}
+	}
+
+	return a, nil
+}
+
+// Import imports the archive into the database, which must be empty. Either
+// everything is imported or nothing is.
+func Import(ctx context.Context, database *db.Database, a *Archive) error {
+	if a.FormatVersion > FormatVersion {
+		return fmt.Errorf("archive has format version %d, but this build only supports up to %d", a.FormatVersion, FormatVersion)
+	}
+
+	err := database.Tx(func(q *db.Queries) error {
+		if err := checkEmpty(ctx, q); err != nil {
+			return err
+		}
+
+		for _, team := range a.Teams {
+			if err := q.ImportTeam(ctx, db.ImportTeamParams{
+				TeamName:         team.Name,
+				CreatedAt:        db.NewDateTime(team.CreatedAt),
+				InviteCode:       team.InviteCode,
+				AcceptingMembers: team.AcceptingMembers,
+				Division:         team.Division,
+			}); err != nil {
+				return fmt.Errorf("failed to import team %q: %w", team.Name, err)
+			}
+
+			for _, member := range team.Members {
+				if _, err := q.JoinTeam(ctx, db.JoinTeamParams{
+					TeamName: team.Name,
+					Username: member.Username,
+					IsLeader: member.IsLeader,
+					JoinedAt: db.NewDateTime(member.JoinedAt),
+				}); err != nil {
+					return fmt.Errorf("failed to import member %q of team %q: %w", member.Username, team.Name, err)
+				}
+			}
+
+			if submission := team.HackathonSubmission; submission != nil {
+				if err := q.ImportHackathonSubmission(ctx, db.ImportHackathonSubmissionParams{
+					TeamName:           team.Name,
+					SubmittedAt:        db.NewDateTime(submission.SubmittedAt),
+					ProjectUrl:         submission.ProjectURL,
+					ProjectDescription: nullString(submission.ProjectDescription),
+					Category:           submission.Category,
+					WonRank:            nullInt64(submission.WonRank),
+				}); err != nil {
+					return fmt.Errorf("failed to import hackathon submission of team %q: %w", team.Name, err)
+				}
+			}
+		}
+
+		for _, submission := range a.Submissions {
+			if err := q.ImportSubmitAttempt(ctx, db.ImportSubmitAttemptParams{
+				ID:          submission.ID,
+				TeamName:    submission.TeamName,
+				ProblemID:   submission.ProblemID,
+				SubmittedAt: db.NewDateTime(submission.SubmittedAt),
+				Correct:     submission.Correct,
+				SubmittedBy: nullString(submission.SubmittedBy),
+				Practice:    submission.Practice,
+				Answer:      nullInt64(submission.Answer),
+			}); err != nil {
+				return fmt.Errorf("failed to import submission %d: %w", submission.ID, err)
+			}
+		}
+
+		for _, p := range a.Points {
+			if err := q.ImportPoints(ctx, db.ImportPointsParams{
+				ID:           p.ID,
+				TeamName:     p.TeamName,
+				AddedAt:      db.NewDateTime(p.AddedAt),
+				Points:       p.Points,
+				Reason:       p.Reason,
+				ProblemID:    nullString(p.ProblemID),
+				Source:       p.Source,
+				Part:         nullInt64(p.Part),
+				SubmissionID: nullInt64(p.SubmissionID),
+				Actor:        nullString(p.Actor),
+			}); err != nil {
+				return fmt.Errorf("failed to import points %d: %w", p.ID, err)
+			}
+		}
+
+		for _, override := range a.ScheduleOverrides {
+			if err := q.ImportScheduleOverride(ctx, db.ImportScheduleOverrideParams{
+				ID:           override.ID,
+				CreatedAt:    db.NewDateTime(override.CreatedAt),
+				Action:       override.Action,
+				ProblemIndex: nullInt64(override.ProblemIndex),
+				DelaySeconds: nullInt64(override.DelaySeconds),
+				Reason:       override.Reason,
+				Division:     override.Division,
+			}); err != nil {
+				return fmt.Errorf("failed to import schedule override %d: %w", override.ID, err)
+			}
+		}
+
+		for _, v := range a.VoidedProblems {
+			if _, err := q.VoidProblem(ctx, db.VoidProblemParams{
+				Division:  v.Division,
+				ProblemID: v.ProblemID,
+				Credit:    v.Credit,
+				Reason:    v.Reason,
+				VoidedAt:  db.NewDateTime(v.VoidedAt),
+			}); err != nil {
+				return fmt.Errorf("failed to import voided problem %q: %w", v.ProblemID, err)
+			}
+		}
+
+		for _, solve := range a.FirstSolves {
+			if _, err := q.AddFirstSolve(ctx, db.AddFirstSolveParams{
+				Division:  solve.Division,
+				ProblemID: solve.ProblemID,
+				Rank:      solve.Rank,
+				TeamName:  solve.TeamName,
+				SolvedAt:  db.NewDateTime(solve.SolvedAt),
+				Bonus:     solve.Bonus,
+			}); err != nil {
+				return fmt.Errorf("failed to import first solve of %q: %w", solve.ProblemID, err)
+			}
+		}
+
+		for _, start := range a.ProblemStarts {
+			if err := q.StartProblem(ctx, db.StartProblemParams{
+				TeamName:  start.TeamName,
+				ProblemID: start.ProblemID,
+				StartedAt: db.NewDateTime(start.StartedAt),
+				StartedBy: start.StartedBy,
+			}); err != nil {
+				return fmt.Errorf("failed to import problem start of %q: %w", start.ProblemID, err)
+			}
+		}
+
+		for _, reveal := range a.LeaderboardReveals {
+			if _, err := q.SetLeaderboardReveal(ctx, db.SetLeaderboardRevealParams{
+				Division:  reveal.Division,
+				Revealed:  reveal.Revealed,
+				UpdatedAt: db.NewDateTime(reveal.UpdatedAt),
+			}); err != nil {
+				return fmt.Errorf("failed to import leaderboard reveal of %q: %w", reveal.Division, err)
+			}
+		}
+
+		for _, entry := range a.AuditLog {
+			if err := q.ImportAuditLog(ctx, db.ImportAuditLogParams{
+				ID:        entry.ID,
+				CreatedAt: db.NewDateTime(entry.CreatedAt),
+				Actor:     entry.Actor,
+				Action:    entry.Action,
+				Arguments: compactJSON(entry.Arguments),
+				Before:    nullJSON(entry.Before),
+				After:     nullJSON(entry.After),
+			}); err != nil {
+				return fmt.Errorf("failed to import audit log entry %d: %w", entry.ID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return database.RestartIDSequences(ctx)
+}
+
+// checkEmpty returns an error if the database has anything that an import
+// could conflict with. Tables that reference teams are empty if there are no
+// teams.
+func checkEmpty(ctx context.Context, q *db.Queries) error {
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list teams: %w", err)
+	}
+	overrides, err := q.ListScheduleOverrides(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+	voided, err := q.ListVoidedProblems(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list voided problems: %w", err)
+	}
+	reveals, err := q.ListLeaderboardReveals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list leaderboard reveals: %w", err)
+	}
+	auditLog, err := q.ListAuditLog(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list audit log: %w", err)
+	}
+	if len(teams)+len(overrides)+len(voided)+len(reveals)+len(auditLog) > 0 {
+		return fmt.Errorf("database is not empty, archives can only be imported into an empty database")
+	}
+	return nil
+}
+
+// Write writes the archive to path. Paths ending in .json get only the JSON
+// of the archive, anything else gets a zip archive that also has its files.
+func (a *Archive) Write(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) == ".json" {
+		err = a.writeJSON(f)
+	} else {
+		err = a.writeZip(f)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return f.Close()
+}
+
+func (a *Archive) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+func (a *Archive) writeZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	f, err := a.createZipFile(zw, archiveFile)
+	if err != nil {
+		return err
+	}
+	if err := a.writeJSON(f); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(a.Files))
+	for name := range a.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		f, err := a.createZipFile(zw, name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(a.Files[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// createZipFile adds a file to the zip archive that is dated at the export.
+func (a *Archive) createZipFile(zw *zip.Writer, name string) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: a.ExportedAt,
+	})
+}
+
+// Read reads the archive at path, which is either a zip archive or the JSON
+// of an archive.
+func Read(path string) (*Archive, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{Files: make(map[string][]byte)}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		if err := json.Unmarshal(b, a); err != nil {
+			return nil, fmt.Errorf("archive is neither a zip archive nor JSON: %w", err)
+		}
+		return a, nil
+	}
+
+	var found bool
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		b, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+
+		if f.Name == archiveFile {
+			if err := json.Unmarshal(b, a); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", archiveFile, err)
+			}
+			found = true
+			continue
+		}
+
+		a.Files[f.Name] = b
+	}
+
+	if !found {
+		return nil, fmt.Errorf("zip archive has no %s", archiveFile)
+	}
+
+	return a, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func int64Ptr(i sql.NullInt64) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullInt64(i *int64) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *i, Valid: true}
+}
+
+func rawJSON(s sql.NullString) json.RawMessage {
+	if !s.Valid {
+		return nil
+	}
+	return json.RawMessage(s.String)
+}
+
+// compactJSON undoes the indentation that JSON values get when the archive
+// is written, so that they are stored as they were.
+func compactJSON(b json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return string(b)
+	}
+	return buf.String()
+}
+
+func nullJSON(b json.RawMessage) sql.NullString {
+	if len(b) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: compactJSON(b), Valid: true}
+}
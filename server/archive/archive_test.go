@@ -0,0 +1,153 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	at := func(minutes int) db.DateTime {
+		return db.NewDateTime(time.Date(2024, 3, 20, 12, minutes, 0, 0, time.UTC))
+	}
+
+	source, err := db.NewInMemory()
+	assert.NoError(t, err)
+	defer source.Close()
+
+	_, err = source.CreateTeam(ctx, db.CreateTeamParams{
+		TeamName:   "team",
+		InviteCode: "code",
+		Division:   "upper",
+		CreatedAt:  at(0),
+	})
+	assert.NoError(t, err)
+
+	_, err = source.JoinTeam(ctx, db.JoinTeamParams{
+		TeamName: "team",
+		Username: "user",
+		IsLeader: true,
+		JoinedAt: at(1),
+	})
+	assert.NoError(t, err)
+
+	submission, err := source.RecordSubmission(ctx, db.RecordSubmissionParams{
+		TeamName:    "team",
+		SubmittedBy: sql.NullString{String: "user", Valid: true},
+		ProblemID:   "problem/part1",
+		Correct:     true,
+		Answer:      sql.NullInt64{Int64: 42, Valid: true},
+		SubmittedAt: at(2),
+	})
+	assert.NoError(t, err)
+
+	_, err = source.AddPoints(ctx, db.AddPointsParams{
+		TeamName:     "team",
+		Points:       99.5,
+		Reason:       "week of code",
+		Source:       "solve",
+		ProblemID:    sql.NullString{String: "problem/part1", Valid: true},
+		Part:         sql.NullInt64{Int64: 1, Valid: true},
+		SubmissionID: sql.NullInt64{Int64: submission.ID, Valid: true},
+		AddedAt:      at(2),
+	})
+	assert.NoError(t, err)
+
+	_, err = source.AddPoints(ctx, db.AddPointsParams{
+		TeamName: "team",
+		Points:   10,
+		Reason:   "bonus",
+		Source:   "admin",
+		Actor:    sql.NullString{String: "admin", Valid: true},
+		AddedAt:  at(3),
+	})
+	assert.NoError(t, err)
+
+	err = source.SetHackathonSubmission(ctx, db.SetHackathonSubmissionParams{
+		TeamName:    "team",
+		ProjectUrl:  "https://example.com",
+		Category:    "category",
+		SubmittedAt: at(4),
+	})
+	assert.NoError(t, err)
+
+	err = source.SetHackathonWinner(ctx, db.SetHackathonWinnerParams{
+		TeamName: "team",
+		WonRank:  sql.NullInt64{Int64: 1, Valid: true},
+	})
+	assert.NoError(t, err)
+
+	_, err = source.AddScheduleOverride(ctx, db.AddScheduleOverrideParams{
+		Division:     "upper",
+		Action:       "delay",
+		ProblemIndex: sql.NullInt64{Int64: 0, Valid: true},
+		DelaySeconds: sql.NullInt64{Int64: 3600, Valid: true},
+		CreatedAt:    at(4),
+	})
+	assert.NoError(t, err)
+
+	_, err = source.AddFirstSolve(ctx, db.AddFirstSolveParams{
+		Division:  "upper",
+		ProblemID: "problem/part1",
+		Rank:      1,
+		TeamName:  "team",
+		SolvedAt:  at(2),
+		Bonus:     30,
+	})
+	assert.NoError(t, err)
+
+	err = source.StartProblem(ctx, db.StartProblemParams{
+		TeamName:  "team",
+		ProblemID: "problem",
+		StartedAt: at(1),
+		StartedBy: "user",
+	})
+	assert.NoError(t, err)
+
+	_, err = source.AddAuditLog(ctx, db.AddAuditLogParams{
+		Actor:     "admin",
+		Action:    "award-points",
+		Arguments: `{"points":10,"reason":"bonus","team":"team"}`,
+		After:     sql.NullString{String: `[{"id":2,"points":10}]`, Valid: true},
+		CreatedAt: at(3),
+	})
+	assert.NoError(t, err)
+
+	exported, err := Export(ctx, source.Queries, time.Now())
+	assert.NoError(t, err)
+	exported.Files["problems/upper/problem/README.md"] = []byte("# Problem")
+
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	assert.NoError(t, exported.Write(path))
+
+	read, err := Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, exported.Files, read.Files)
+
+	target, err := db.NewInMemory()
+	assert.NoError(t, err)
+	defer target.Close()
+
+	assert.NoError(t, Import(ctx, target, read))
+	assert.Error(t, Import(ctx, target, read), "importing into a non-empty database must fail")
+
+	reexported, err := Export(ctx, target.Queries, exported.ExportedAt)
+	assert.NoError(t, err)
+	reexported.Files = exported.Files
+	assert.Equal(t, exported, reexported)
+
+	// New rows must not collide with the imported ones.
+	next, err := target.RecordSubmission(ctx, db.RecordSubmissionParams{
+		TeamName:    "team",
+		ProblemID:   "problem/part2",
+		SubmittedAt: at(5),
+	})
+	assert.NoError(t, err)
+	assert.True(t, next.ID > submission.ID)
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+)
+
+// AuditEntry is a change made by an admin, to be recorded in the audit log.
+type AuditEntry struct {
+	// Actor is who made the change.
+	Actor string
+	// Action is what the change was, such as the competitionctl command that
+	// made it.
+	Action string
+	// Arguments are the arguments of the action. Before and After are the
+	// values that the action changed, as they were before and after it. All
+	// three are stored as JSON, and Before and After may be nil.
+	Arguments any
+	Before    any
+	After     any
+}
+
+// RecordAudit adds the entry to the audit log. It should be called in the
+// same transaction as the change that it records.
+func RecordAudit(ctx context.Context, q *db.Queries, entry AuditEntry, t time.Time) error {
+	if entry.Actor == "" {
+		return fmt.Errorf("audit log entry has no actor")
+	}
+
+	arguments := []byte("{}")
+	if entry.Arguments != nil {
+		var err error
+		arguments, err = json.Marshal(entry.Arguments)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit log arguments: %w", err)
+		}
+	}
+
+	before, err := auditValue(entry.Before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log before value: %w", err)
+	}
+
+	after, err := auditValue(entry.After)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log after value: %w", err)
+	}
+
+	if _, err := q.AddAuditLog(ctx, db.AddAuditLogParams{
+		Actor:     entry.Actor,
+		Action:    entry.Action,
+		Arguments: string(arguments),
+		Before:    before,
+		After:     after,
+		CreatedAt: db.NewDateTime(t),
+	}); err != nil {
+		return fmt.Errorf("failed to add audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+func auditValue(v any) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// adminActorHeader is the header that admin requests may name their actor
+// with for the audit log, since everyone shares the admin token.
+const adminActorHeader = "X-Admin-Actor"
+
+// adminActor returns the actor of an admin request for the audit log.
+func adminActor(r *http.Request) string {
+	if actor := r.Header.Get(adminActorHeader); actor != "" {
+		return actor
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "admin token from " + host
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+)
+
+// snapshotTimeFormat is the time format in snapshot file names. It sorts in
+// chronological order.
+const snapshotTimeFormat = "20060102T150405Z"
+
+// SnapshotName returns the file name of a snapshot taken at t. The prefix
+// tells scheduled snapshots apart from others, e.g. those taken before a
+// restore, which are never deleted.
+func SnapshotName(prefix string, t time.Time) string {
+	return prefix + "-" + t.UTC().Format(snapshotTimeFormat) + ".sqlite"
+}
+
+// ListSnapshots returns the paths of the scheduled snapshots in dir, oldest
+// first.
+func ListSnapshots(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "snapshot-*.sqlite"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// TakeSnapshot writes a new snapshot of the database into the backups
+// directory, then deletes the oldest snapshots beyond those to keep. It
+// returns the path of the new snapshot.
+func TakeSnapshot(ctx context.Context, database *db.Database, cfg config.BackupsConfig) (string, error) {
+	if cfg.Dir == "" {
+		return "", fmt.Errorf("no backups dir is configured")
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backups dir: %w", err)
+	}
+
+	// Snapshots are named by the real time rather than the competition
+	// clock, which may be simulated.
+	path := filepath.Join(cfg.Dir, SnapshotName("snapshot", time.Now()))
+	if err := database.Backup(ctx, path); err != nil {
+		return "", err
+	}
+
+	if cfg.Keep > 0 {
+		snapshots, err := ListSnapshots(cfg.Dir)
+		if err != nil {
+			return path, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		for len(snapshots) > cfg.Keep {
+			if err := os.Remove(snapshots[0]); err != nil {
+				return path, fmt.Errorf("failed to delete old snapshot: %w", err)
+			}
+			snapshots = snapshots[1:]
+		}
+	}
+
+	return path, nil
+}
+
+// ScheduleBackups takes a snapshot of the database every cfg.Every in the
+// background until ctx is canceled. It does nothing if no schedule is
+// configured.
+func (s *Server) ScheduleBackups(ctx context.Context, cfg config.BackupsConfig) error {
+	if !cfg.IsScheduled() {
+		return nil
+	}
+
+	if s.database.Dialect() != db.SQLite {
+		return db.ErrBackupUnsupported
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.Every.Duration())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				path, err := TakeSnapshot(ctx, s.database, cfg)
+				if err != nil {
+					s.logger.ErrorContext(ctx,
+						"failed to take snapshot",
+						"err", err)
+					continue
+				}
+				s.logger.InfoContext(ctx,
+					"took snapshot",
+					"path", path)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+)
+
+// cooldownEnd returns the end of the user's cooldown for submitting a part of
+// the problem, following the problem's cooldown policy. It returns the zero
+// time if there is no cooldown.
+func cooldownEnd(ctx context.Context, q *db.Queries, p *problem.Problem, division *Division, day problemDay, part2 bool, u authenticatedUser) (time.Time, error) {
+	policy := p.Cooldown
+
+	problemIDs := []string{division.problemID(day, part2)}
+	if policy.Scope == problem.CooldownPerTeam {
+		problemIDs = []string{division.problemID(day, false), division.problemID(day, true)}
+	}
+
+	var attempts int
+	var lastAttempt time.Time
+
+	for _, problemID := range problemIDs {
+		submissions, err := q.ListSubmissions(ctx, db.ListSubmissionsParams{
+			TeamName:  u.TeamName,
+			ProblemID: problemID,
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to list submissions: %w", err)
+		}
+
+		for _, submission := range submissions {
+			if submission.Correct {
+				continue
+			}
+			if policy.Scope == problem.CooldownPerUser && submission.SubmittedBy.String != u.Username {
+				continue
+			}
+			attempts++
+			if t := submission.SubmittedAt.Time(); t.After(lastAttempt) {
+				lastAttempt = t
+			}
+		}
+	}
+
+	return policy.CooldownEnd(attempts, lastAttempt), nil
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"libdb.so/lazymigrate"
+)
+
+// ErrBackupUnsupported is returned when backing up or restoring a database
+// whose dialect has its own tools for that, such as pg_dump for PostgreSQL.
+var ErrBackupUnsupported = errors.New("backups are only supported for SQLite, use the database's own tools instead")
+
+// SchemaVersion returns the schema version that this build of the server
+// migrates SQLite databases to.
+func SchemaVersion() int {
+	return len(lazymigrate.NewSchema(schema).Versions())
+}
+
+// Backup writes a consistent snapshot of the database to path while the
+// database stays in use. The snapshot is written to a temporary file first,
+// so path either holds a complete snapshot or doesn't exist.
+func (db *Database) Backup(ctx context.Context, path string) error {
+	if db.dialect != SQLite {
+		return ErrBackupUnsupported
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+
+	if _, err := db.db.ExecContext(ctx, "VACUUM INTO ?", tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to move snapshot into place: %w", err)
+	}
+
+	return nil
+}
+
+// CheckSnapshot checks that the SQLite snapshot at path is intact and can be
+// restored by this build, and returns its schema version. Snapshots with an
+// older schema version than [SchemaVersion] are migrated when the database is
+// next opened, but newer ones can't be.
+func CheckSnapshot(ctx context.Context, path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var integrity string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return 0, fmt.Errorf("failed to check integrity: %w", err)
+	}
+	if integrity != "ok" {
+		return 0, fmt.Errorf("integrity check failed: %s", integrity)
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if version == 0 {
+		return 0, fmt.Errorf("it has no schema")
+	}
+	if latest := SchemaVersion(); version > latest {
+		return version, fmt.Errorf("it has schema version %d, but this build only supports up to %d", version, latest)
+	}
+
+	return version, nil
+}
+
+// Restore replaces the SQLite database at path with the snapshot, after
+// checking it with [CheckSnapshot]. Nothing may have the database open while
+// it is restored.
+func Restore(ctx context.Context, snapshot, path string) error {
+	if isPostgresURL(path) {
+		return ErrBackupUnsupported
+	}
+	path = strings.TrimPrefix(path, "sqlite://")
+
+	if _, err := CheckSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", snapshot, err)
+	}
+
+	// Copy the snapshot next to the database first, so that the database is
+	// replaced in one rename.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	src, err := os.Open(snapshot)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+
+	// The write-ahead log of the old database must not be applied to the
+	// restored one.
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path+suffix, err)
+		}
+	}
+
+	return nil
+}
@@ -24,12 +24,27 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addAuditLogStmt, err = db.PrepareContext(ctx, addAuditLog); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAuditLog: %w", err)
+	}
+	if q.addFirstSolveStmt, err = db.PrepareContext(ctx, addFirstSolve); err != nil {
+		return nil, fmt.Errorf("error preparing query AddFirstSolve: %w", err)
+	}
 	if q.addPointsStmt, err = db.PrepareContext(ctx, addPoints); err != nil {
 		return nil, fmt.Errorf("error preparing query AddPoints: %w", err)
 	}
+	if q.addScheduleOverrideStmt, err = db.PrepareContext(ctx, addScheduleOverride); err != nil {
+		return nil, fmt.Errorf("error preparing query AddScheduleOverride: %w", err)
+	}
+	if q.countFirstSolvesStmt, err = db.PrepareContext(ctx, countFirstSolves); err != nil {
+		return nil, fmt.Errorf("error preparing query CountFirstSolves: %w", err)
+	}
 	if q.countIncorrectSubmissionsStmt, err = db.PrepareContext(ctx, countIncorrectSubmissions); err != nil {
 		return nil, fmt.Errorf("error preparing query CountIncorrectSubmissions: %w", err)
 	}
+	if q.countSolvesStmt, err = db.PrepareContext(ctx, countSolves); err != nil {
+		return nil, fmt.Errorf("error preparing query CountSolves: %w", err)
+	}
 	if q.createTeamStmt, err = db.PrepareContext(ctx, createTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTeam: %w", err)
 	}
@@ -42,6 +57,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.findTeamWithInviteCodeStmt, err = db.PrepareContext(ctx, findTeamWithInviteCode); err != nil {
 		return nil, fmt.Errorf("error preparing query FindTeamWithInviteCode: %w", err)
 	}
+	if q.getLeaderboardRevealStmt, err = db.PrepareContext(ctx, getLeaderboardReveal); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLeaderboardReveal: %w", err)
+	}
 	if q.hackathonSubmissionStmt, err = db.PrepareContext(ctx, hackathonSubmission); err != nil {
 		return nil, fmt.Errorf("error preparing query HackathonSubmission: %w", err)
 	}
@@ -54,6 +72,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.hasSolvedStmt, err = db.PrepareContext(ctx, hasSolved); err != nil {
 		return nil, fmt.Errorf("error preparing query HasSolved: %w", err)
 	}
+	if q.hasSubmittedWrongAnswerStmt, err = db.PrepareContext(ctx, hasSubmittedWrongAnswer); err != nil {
+		return nil, fmt.Errorf("error preparing query HasSubmittedWrongAnswer: %w", err)
+	}
+	if q.importAuditLogStmt, err = db.PrepareContext(ctx, importAuditLog); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportAuditLog: %w", err)
+	}
+	if q.importHackathonSubmissionStmt, err = db.PrepareContext(ctx, importHackathonSubmission); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportHackathonSubmission: %w", err)
+	}
+	if q.importPointsStmt, err = db.PrepareContext(ctx, importPoints); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportPoints: %w", err)
+	}
+	if q.importScheduleOverrideStmt, err = db.PrepareContext(ctx, importScheduleOverride); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportScheduleOverride: %w", err)
+	}
+	if q.importSubmitAttemptStmt, err = db.PrepareContext(ctx, importSubmitAttempt); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportSubmitAttempt: %w", err)
+	}
+	if q.importTeamStmt, err = db.PrepareContext(ctx, importTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportTeam: %w", err)
+	}
 	if q.isLeaderStmt, err = db.PrepareContext(ctx, isLeader); err != nil {
 		return nil, fmt.Errorf("error preparing query IsLeader: %w", err)
 	}
@@ -69,9 +108,42 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listAllCorrectSubmissionsStmt, err = db.PrepareContext(ctx, listAllCorrectSubmissions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAllCorrectSubmissions: %w", err)
 	}
+	if q.listAllSubmissionsStmt, err = db.PrepareContext(ctx, listAllSubmissions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAllSubmissions: %w", err)
+	}
+	if q.listAllTeamMembersStmt, err = db.PrepareContext(ctx, listAllTeamMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAllTeamMembers: %w", err)
+	}
+	if q.listAllTeamsStmt, err = db.PrepareContext(ctx, listAllTeams); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAllTeams: %w", err)
+	}
+	if q.listAuditLogStmt, err = db.PrepareContext(ctx, listAuditLog); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditLog: %w", err)
+	}
+	if q.listFirstSolvesStmt, err = db.PrepareContext(ctx, listFirstSolves); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFirstSolves: %w", err)
+	}
+	if q.listLeaderboardRevealsStmt, err = db.PrepareContext(ctx, listLeaderboardReveals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLeaderboardReveals: %w", err)
+	}
+	if q.listPointsStmt, err = db.PrepareContext(ctx, listPoints); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPoints: %w", err)
+	}
+	if q.listProblemStartsStmt, err = db.PrepareContext(ctx, listProblemStarts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListProblemStarts: %w", err)
+	}
+	if q.listScheduleOverridesStmt, err = db.PrepareContext(ctx, listScheduleOverrides); err != nil {
+		return nil, fmt.Errorf("error preparing query ListScheduleOverrides: %w", err)
+	}
 	if q.listSubmissionsStmt, err = db.PrepareContext(ctx, listSubmissions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSubmissions: %w", err)
 	}
+	if q.listSubmitAttemptsStmt, err = db.PrepareContext(ctx, listSubmitAttempts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSubmitAttempts: %w", err)
+	}
+	if q.listSubmittedProblemIDsStmt, err = db.PrepareContext(ctx, listSubmittedProblemIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSubmittedProblemIDs: %w", err)
+	}
 	if q.listTeamAndMembersStmt, err = db.PrepareContext(ctx, listTeamAndMembers); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTeamAndMembers: %w", err)
 	}
@@ -81,6 +153,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listTeamsStmt, err = db.PrepareContext(ctx, listTeams); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTeams: %w", err)
 	}
+	if q.listVoidedProblemsStmt, err = db.PrepareContext(ctx, listVoidedProblems); err != nil {
+		return nil, fmt.Errorf("error preparing query ListVoidedProblems: %w", err)
+	}
+	if q.problemPointsByTeamStmt, err = db.PrepareContext(ctx, problemPointsByTeam); err != nil {
+		return nil, fmt.Errorf("error preparing query ProblemPointsByTeam: %w", err)
+	}
+	if q.problemStartTimeStmt, err = db.PrepareContext(ctx, problemStartTime); err != nil {
+		return nil, fmt.Errorf("error preparing query ProblemStartTime: %w", err)
+	}
 	if q.recordSubmissionStmt, err = db.PrepareContext(ctx, recordSubmission); err != nil {
 		return nil, fmt.Errorf("error preparing query RecordSubmission: %w", err)
 	}
@@ -90,39 +171,88 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.removePointsByTimeStmt, err = db.PrepareContext(ctx, removePointsByTime); err != nil {
 		return nil, fmt.Errorf("error preparing query RemovePointsByTime: %w", err)
 	}
+	if q.renameFirstSolvesProblemIDStmt, err = db.PrepareContext(ctx, renameFirstSolvesProblemID); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameFirstSolvesProblemID: %w", err)
+	}
+	if q.renamePointsProblemIDStmt, err = db.PrepareContext(ctx, renamePointsProblemID); err != nil {
+		return nil, fmt.Errorf("error preparing query RenamePointsProblemID: %w", err)
+	}
+	if q.renameProblemStartsProblemIDStmt, err = db.PrepareContext(ctx, renameProblemStartsProblemID); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameProblemStartsProblemID: %w", err)
+	}
+	if q.renameSubmissionsProblemIDStmt, err = db.PrepareContext(ctx, renameSubmissionsProblemID); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameSubmissionsProblemID: %w", err)
+	}
+	if q.renameVoidedProblemIDStmt, err = db.PrepareContext(ctx, renameVoidedProblemID); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameVoidedProblemID: %w", err)
+	}
+	if q.resetLeaderboardRevealStmt, err = db.PrepareContext(ctx, resetLeaderboardReveal); err != nil {
+		return nil, fmt.Errorf("error preparing query ResetLeaderboardReveal: %w", err)
+	}
 	if q.setHackathonSubmissionStmt, err = db.PrepareContext(ctx, setHackathonSubmission); err != nil {
 		return nil, fmt.Errorf("error preparing query SetHackathonSubmission: %w", err)
 	}
 	if q.setHackathonWinnerStmt, err = db.PrepareContext(ctx, setHackathonWinner); err != nil {
 		return nil, fmt.Errorf("error preparing query SetHackathonWinner: %w", err)
 	}
+	if q.setLeaderboardRevealStmt, err = db.PrepareContext(ctx, setLeaderboardReveal); err != nil {
+		return nil, fmt.Errorf("error preparing query SetLeaderboardReveal: %w", err)
+	}
+	if q.startProblemStmt, err = db.PrepareContext(ctx, startProblem); err != nil {
+		return nil, fmt.Errorf("error preparing query StartProblem: %w", err)
+	}
+	if q.teamDivisionStmt, err = db.PrepareContext(ctx, teamDivision); err != nil {
+		return nil, fmt.Errorf("error preparing query TeamDivision: %w", err)
+	}
 	if q.teamInviteCodeStmt, err = db.PrepareContext(ctx, teamInviteCode); err != nil {
 		return nil, fmt.Errorf("error preparing query TeamInviteCode: %w", err)
 	}
-	if q.teamPointsEachStmt, err = db.PrepareContext(ctx, teamPointsEach); err != nil {
-		return nil, fmt.Errorf("error preparing query TeamPointsEach: %w", err)
-	}
 	if q.teamPointsHistoryStmt, err = db.PrepareContext(ctx, teamPointsHistory); err != nil {
 		return nil, fmt.Errorf("error preparing query TeamPointsHistory: %w", err)
 	}
-	if q.teamPointsTotalStmt, err = db.PrepareContext(ctx, teamPointsTotal); err != nil {
-		return nil, fmt.Errorf("error preparing query TeamPointsTotal: %w", err)
+	if q.voidProblemStmt, err = db.PrepareContext(ctx, voidProblem); err != nil {
+		return nil, fmt.Errorf("error preparing query VoidProblem: %w", err)
 	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addAuditLogStmt != nil {
+		if cerr := q.addAuditLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAuditLogStmt: %w", cerr)
+		}
+	}
+	if q.addFirstSolveStmt != nil {
+		if cerr := q.addFirstSolveStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addFirstSolveStmt: %w", cerr)
+		}
+	}
 	if q.addPointsStmt != nil {
 		if cerr := q.addPointsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addPointsStmt: %w", cerr)
 		}
 	}
+	if q.addScheduleOverrideStmt != nil {
+		if cerr := q.addScheduleOverrideStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addScheduleOverrideStmt: %w", cerr)
+		}
+	}
+	if q.countFirstSolvesStmt != nil {
+		if cerr := q.countFirstSolvesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countFirstSolvesStmt: %w", cerr)
+		}
+	}
 	if q.countIncorrectSubmissionsStmt != nil {
 		if cerr := q.countIncorrectSubmissionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countIncorrectSubmissionsStmt: %w", cerr)
 		}
 	}
+	if q.countSolvesStmt != nil {
+		if cerr := q.countSolvesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countSolvesStmt: %w", cerr)
+		}
+	}
 	if q.createTeamStmt != nil {
 		if cerr := q.createTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTeamStmt: %w", cerr)
@@ -143,6 +273,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing findTeamWithInviteCodeStmt: %w", cerr)
 		}
 	}
+	if q.getLeaderboardRevealStmt != nil {
+		if cerr := q.getLeaderboardRevealStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLeaderboardRevealStmt: %w", cerr)
+		}
+	}
 	if q.hackathonSubmissionStmt != nil {
 		if cerr := q.hackathonSubmissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing hackathonSubmissionStmt: %w", cerr)
@@ -163,6 +298,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing hasSolvedStmt: %w", cerr)
 		}
 	}
+	if q.hasSubmittedWrongAnswerStmt != nil {
+		if cerr := q.hasSubmittedWrongAnswerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing hasSubmittedWrongAnswerStmt: %w", cerr)
+		}
+	}
+	if q.importAuditLogStmt != nil {
+		if cerr := q.importAuditLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importAuditLogStmt: %w", cerr)
+		}
+	}
+	if q.importHackathonSubmissionStmt != nil {
+		if cerr := q.importHackathonSubmissionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importHackathonSubmissionStmt: %w", cerr)
+		}
+	}
+	if q.importPointsStmt != nil {
+		if cerr := q.importPointsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importPointsStmt: %w", cerr)
+		}
+	}
+	if q.importScheduleOverrideStmt != nil {
+		if cerr := q.importScheduleOverrideStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importScheduleOverrideStmt: %w", cerr)
+		}
+	}
+	if q.importSubmitAttemptStmt != nil {
+		if cerr := q.importSubmitAttemptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importSubmitAttemptStmt: %w", cerr)
+		}
+	}
+	if q.importTeamStmt != nil {
+		if cerr := q.importTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importTeamStmt: %w", cerr)
+		}
+	}
 	if q.isLeaderStmt != nil {
 		if cerr := q.isLeaderStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing isLeaderStmt: %w", cerr)
@@ -188,11 +358,66 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listAllCorrectSubmissionsStmt: %w", cerr)
 		}
 	}
+	if q.listAllSubmissionsStmt != nil {
+		if cerr := q.listAllSubmissionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAllSubmissionsStmt: %w", cerr)
+		}
+	}
+	if q.listAllTeamMembersStmt != nil {
+		if cerr := q.listAllTeamMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAllTeamMembersStmt: %w", cerr)
+		}
+	}
+	if q.listAllTeamsStmt != nil {
+		if cerr := q.listAllTeamsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAllTeamsStmt: %w", cerr)
+		}
+	}
+	if q.listAuditLogStmt != nil {
+		if cerr := q.listAuditLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditLogStmt: %w", cerr)
+		}
+	}
+	if q.listFirstSolvesStmt != nil {
+		if cerr := q.listFirstSolvesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFirstSolvesStmt: %w", cerr)
+		}
+	}
+	if q.listLeaderboardRevealsStmt != nil {
+		if cerr := q.listLeaderboardRevealsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLeaderboardRevealsStmt: %w", cerr)
+		}
+	}
+	if q.listPointsStmt != nil {
+		if cerr := q.listPointsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPointsStmt: %w", cerr)
+		}
+	}
+	if q.listProblemStartsStmt != nil {
+		if cerr := q.listProblemStartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listProblemStartsStmt: %w", cerr)
+		}
+	}
+	if q.listScheduleOverridesStmt != nil {
+		if cerr := q.listScheduleOverridesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listScheduleOverridesStmt: %w", cerr)
+		}
+	}
 	if q.listSubmissionsStmt != nil {
 		if cerr := q.listSubmissionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSubmissionsStmt: %w", cerr)
 		}
 	}
+	if q.listSubmitAttemptsStmt != nil {
+		if cerr := q.listSubmitAttemptsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSubmitAttemptsStmt: %w", cerr)
+		}
+	}
+	if q.listSubmittedProblemIDsStmt != nil {
+		if cerr := q.listSubmittedProblemIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSubmittedProblemIDsStmt: %w", cerr)
+		}
+	}
 	if q.listTeamAndMembersStmt != nil {
 		if cerr := q.listTeamAndMembersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listTeamAndMembersStmt: %w", cerr)
@@ -208,6 +433,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listTeamsStmt: %w", cerr)
 		}
 	}
+	if q.listVoidedProblemsStmt != nil {
+		if cerr := q.listVoidedProblemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listVoidedProblemsStmt: %w", cerr)
+		}
+	}
+	if q.problemPointsByTeamStmt != nil {
+		if cerr := q.problemPointsByTeamStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing problemPointsByTeamStmt: %w", cerr)
+		}
+	}
+	if q.problemStartTimeStmt != nil {
+		if cerr := q.problemStartTimeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing problemStartTimeStmt: %w", cerr)
+		}
+	}
 	if q.recordSubmissionStmt != nil {
 		if cerr := q.recordSubmissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing recordSubmissionStmt: %w", cerr)
@@ -223,6 +463,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing removePointsByTimeStmt: %w", cerr)
 		}
 	}
+	if q.renameFirstSolvesProblemIDStmt != nil {
+		if cerr := q.renameFirstSolvesProblemIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameFirstSolvesProblemIDStmt: %w", cerr)
+		}
+	}
+	if q.renamePointsProblemIDStmt != nil {
+		if cerr := q.renamePointsProblemIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renamePointsProblemIDStmt: %w", cerr)
+		}
+	}
+	if q.renameProblemStartsProblemIDStmt != nil {
+		if cerr := q.renameProblemStartsProblemIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameProblemStartsProblemIDStmt: %w", cerr)
+		}
+	}
+	if q.renameSubmissionsProblemIDStmt != nil {
+		if cerr := q.renameSubmissionsProblemIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameSubmissionsProblemIDStmt: %w", cerr)
+		}
+	}
+	if q.renameVoidedProblemIDStmt != nil {
+		if cerr := q.renameVoidedProblemIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameVoidedProblemIDStmt: %w", cerr)
+		}
+	}
+	if q.resetLeaderboardRevealStmt != nil {
+		if cerr := q.resetLeaderboardRevealStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resetLeaderboardRevealStmt: %w", cerr)
+		}
+	}
 	if q.setHackathonSubmissionStmt != nil {
 		if cerr := q.setHackathonSubmissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing setHackathonSubmissionStmt: %w", cerr)
@@ -233,24 +503,34 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing setHackathonWinnerStmt: %w", cerr)
 		}
 	}
+	if q.setLeaderboardRevealStmt != nil {
+		if cerr := q.setLeaderboardRevealStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setLeaderboardRevealStmt: %w", cerr)
+		}
+	}
+	if q.startProblemStmt != nil {
+		if cerr := q.startProblemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing startProblemStmt: %w", cerr)
+		}
+	}
+	if q.teamDivisionStmt != nil {
+		if cerr := q.teamDivisionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing teamDivisionStmt: %w", cerr)
+		}
+	}
 	if q.teamInviteCodeStmt != nil {
 		if cerr := q.teamInviteCodeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing teamInviteCodeStmt: %w", cerr)
 		}
 	}
-	if q.teamPointsEachStmt != nil {
-		if cerr := q.teamPointsEachStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing teamPointsEachStmt: %w", cerr)
-		}
-	}
 	if q.teamPointsHistoryStmt != nil {
 		if cerr := q.teamPointsHistoryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing teamPointsHistoryStmt: %w", cerr)
 		}
 	}
-	if q.teamPointsTotalStmt != nil {
-		if cerr := q.teamPointsTotalStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing teamPointsTotalStmt: %w", cerr)
+	if q.voidProblemStmt != nil {
+		if cerr := q.voidProblemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing voidProblemStmt: %w", cerr)
 		}
 	}
 	return err
@@ -290,69 +570,139 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                            DBTX
-	tx                            *sql.Tx
-	addPointsStmt                 *sql.Stmt
-	countIncorrectSubmissionsStmt *sql.Stmt
-	createTeamStmt                *sql.Stmt
-	dropTeamStmt                  *sql.Stmt
-	findTeamStmt                  *sql.Stmt
-	findTeamWithInviteCodeStmt    *sql.Stmt
-	hackathonSubmissionStmt       *sql.Stmt
-	hackathonSubmissionsStmt      *sql.Stmt
-	hackathonWinnersStmt          *sql.Stmt
-	hasSolvedStmt                 *sql.Stmt
-	isLeaderStmt                  *sql.Stmt
-	joinTeamStmt                  *sql.Stmt
-	lastSubmissionTimeStmt        *sql.Stmt
-	leaveTeamStmt                 *sql.Stmt
-	listAllCorrectSubmissionsStmt *sql.Stmt
-	listSubmissionsStmt           *sql.Stmt
-	listTeamAndMembersStmt        *sql.Stmt
-	listTeamMembersStmt           *sql.Stmt
-	listTeamsStmt                 *sql.Stmt
-	recordSubmissionStmt          *sql.Stmt
-	removePointsByReasonStmt      *sql.Stmt
-	removePointsByTimeStmt        *sql.Stmt
-	setHackathonSubmissionStmt    *sql.Stmt
-	setHackathonWinnerStmt        *sql.Stmt
-	teamInviteCodeStmt            *sql.Stmt
-	teamPointsEachStmt            *sql.Stmt
-	teamPointsHistoryStmt         *sql.Stmt
-	teamPointsTotalStmt           *sql.Stmt
+	db                               DBTX
+	tx                               *sql.Tx
+	addAuditLogStmt                  *sql.Stmt
+	addFirstSolveStmt                *sql.Stmt
+	addPointsStmt                    *sql.Stmt
+	addScheduleOverrideStmt          *sql.Stmt
+	countFirstSolvesStmt             *sql.Stmt
+	countIncorrectSubmissionsStmt    *sql.Stmt
+	countSolvesStmt                  *sql.Stmt
+	createTeamStmt                   *sql.Stmt
+	dropTeamStmt                     *sql.Stmt
+	findTeamStmt                     *sql.Stmt
+	findTeamWithInviteCodeStmt       *sql.Stmt
+	getLeaderboardRevealStmt         *sql.Stmt
+	hackathonSubmissionStmt          *sql.Stmt
+	hackathonSubmissionsStmt         *sql.Stmt
+	hackathonWinnersStmt             *sql.Stmt
+	hasSolvedStmt                    *sql.Stmt
+	hasSubmittedWrongAnswerStmt      *sql.Stmt
+	importAuditLogStmt               *sql.Stmt
+	importHackathonSubmissionStmt    *sql.Stmt
+	importPointsStmt                 *sql.Stmt
+	importScheduleOverrideStmt       *sql.Stmt
+	importSubmitAttemptStmt          *sql.Stmt
+	importTeamStmt                   *sql.Stmt
+	isLeaderStmt                     *sql.Stmt
+	joinTeamStmt                     *sql.Stmt
+	lastSubmissionTimeStmt           *sql.Stmt
+	leaveTeamStmt                    *sql.Stmt
+	listAllCorrectSubmissionsStmt    *sql.Stmt
+	listAllSubmissionsStmt           *sql.Stmt
+	listAllTeamMembersStmt           *sql.Stmt
+	listAllTeamsStmt                 *sql.Stmt
+	listAuditLogStmt                 *sql.Stmt
+	listFirstSolvesStmt              *sql.Stmt
+	listLeaderboardRevealsStmt       *sql.Stmt
+	listPointsStmt                   *sql.Stmt
+	listProblemStartsStmt            *sql.Stmt
+	listScheduleOverridesStmt        *sql.Stmt
+	listSubmissionsStmt              *sql.Stmt
+	listSubmitAttemptsStmt           *sql.Stmt
+	listSubmittedProblemIDsStmt      *sql.Stmt
+	listTeamAndMembersStmt           *sql.Stmt
+	listTeamMembersStmt              *sql.Stmt
+	listTeamsStmt                    *sql.Stmt
+	listVoidedProblemsStmt           *sql.Stmt
+	problemPointsByTeamStmt          *sql.Stmt
+	problemStartTimeStmt             *sql.Stmt
+	recordSubmissionStmt             *sql.Stmt
+	removePointsByReasonStmt         *sql.Stmt
+	removePointsByTimeStmt           *sql.Stmt
+	renameFirstSolvesProblemIDStmt   *sql.Stmt
+	renamePointsProblemIDStmt        *sql.Stmt
+	renameProblemStartsProblemIDStmt *sql.Stmt
+	renameSubmissionsProblemIDStmt   *sql.Stmt
+	renameVoidedProblemIDStmt        *sql.Stmt
+	resetLeaderboardRevealStmt       *sql.Stmt
+	setHackathonSubmissionStmt       *sql.Stmt
+	setHackathonWinnerStmt           *sql.Stmt
+	setLeaderboardRevealStmt         *sql.Stmt
+	startProblemStmt                 *sql.Stmt
+	teamDivisionStmt                 *sql.Stmt
+	teamInviteCodeStmt               *sql.Stmt
+	teamPointsHistoryStmt            *sql.Stmt
+	voidProblemStmt                  *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                            tx,
-		tx:                            tx,
-		addPointsStmt:                 q.addPointsStmt,
-		countIncorrectSubmissionsStmt: q.countIncorrectSubmissionsStmt,
-		createTeamStmt:                q.createTeamStmt,
-		dropTeamStmt:                  q.dropTeamStmt,
-		findTeamStmt:                  q.findTeamStmt,
-		findTeamWithInviteCodeStmt:    q.findTeamWithInviteCodeStmt,
-		hackathonSubmissionStmt:       q.hackathonSubmissionStmt,
-		hackathonSubmissionsStmt:      q.hackathonSubmissionsStmt,
-		hackathonWinnersStmt:          q.hackathonWinnersStmt,
-		hasSolvedStmt:                 q.hasSolvedStmt,
-		isLeaderStmt:                  q.isLeaderStmt,
-		joinTeamStmt:                  q.joinTeamStmt,
-		lastSubmissionTimeStmt:        q.lastSubmissionTimeStmt,
-		leaveTeamStmt:                 q.leaveTeamStmt,
-		listAllCorrectSubmissionsStmt: q.listAllCorrectSubmissionsStmt,
-		listSubmissionsStmt:           q.listSubmissionsStmt,
-		listTeamAndMembersStmt:        q.listTeamAndMembersStmt,
-		listTeamMembersStmt:           q.listTeamMembersStmt,
-		listTeamsStmt:                 q.listTeamsStmt,
-		recordSubmissionStmt:          q.recordSubmissionStmt,
-		removePointsByReasonStmt:      q.removePointsByReasonStmt,
-		removePointsByTimeStmt:        q.removePointsByTimeStmt,
-		setHackathonSubmissionStmt:    q.setHackathonSubmissionStmt,
-		setHackathonWinnerStmt:        q.setHackathonWinnerStmt,
-		teamInviteCodeStmt:            q.teamInviteCodeStmt,
-		teamPointsEachStmt:            q.teamPointsEachStmt,
-		teamPointsHistoryStmt:         q.teamPointsHistoryStmt,
-		teamPointsTotalStmt:           q.teamPointsTotalStmt,
+		db:                               tx,
+		tx:                               tx,
+		addAuditLogStmt:                  q.addAuditLogStmt,
+		addFirstSolveStmt:                q.addFirstSolveStmt,
+		addPointsStmt:                    q.addPointsStmt,
+		addScheduleOverrideStmt:          q.addScheduleOverrideStmt,
+		countFirstSolvesStmt:             q.countFirstSolvesStmt,
+		countIncorrectSubmissionsStmt:    q.countIncorrectSubmissionsStmt,
+		countSolvesStmt:                  q.countSolvesStmt,
+		createTeamStmt:                   q.createTeamStmt,
+		dropTeamStmt:                     q.dropTeamStmt,
+		findTeamStmt:                     q.findTeamStmt,
+		findTeamWithInviteCodeStmt:       q.findTeamWithInviteCodeStmt,
+		getLeaderboardRevealStmt:         q.getLeaderboardRevealStmt,
+		hackathonSubmissionStmt:          q.hackathonSubmissionStmt,
+		hackathonSubmissionsStmt:         q.hackathonSubmissionsStmt,
+		hackathonWinnersStmt:             q.hackathonWinnersStmt,
+		hasSolvedStmt:                    q.hasSolvedStmt,
+		hasSubmittedWrongAnswerStmt:      q.hasSubmittedWrongAnswerStmt,
+		importAuditLogStmt:               q.importAuditLogStmt,
+		importHackathonSubmissionStmt:    q.importHackathonSubmissionStmt,
+		importPointsStmt:                 q.importPointsStmt,
+		importScheduleOverrideStmt:       q.importScheduleOverrideStmt,
+		importSubmitAttemptStmt:          q.importSubmitAttemptStmt,
+		importTeamStmt:                   q.importTeamStmt,
+		isLeaderStmt:                     q.isLeaderStmt,
+		joinTeamStmt:                     q.joinTeamStmt,
+		lastSubmissionTimeStmt:           q.lastSubmissionTimeStmt,
+		leaveTeamStmt:                    q.leaveTeamStmt,
+		listAllCorrectSubmissionsStmt:    q.listAllCorrectSubmissionsStmt,
+		listAllSubmissionsStmt:           q.listAllSubmissionsStmt,
+		listAllTeamMembersStmt:           q.listAllTeamMembersStmt,
+		listAllTeamsStmt:                 q.listAllTeamsStmt,
+		listAuditLogStmt:                 q.listAuditLogStmt,
+		listFirstSolvesStmt:              q.listFirstSolvesStmt,
+		listLeaderboardRevealsStmt:       q.listLeaderboardRevealsStmt,
+		listPointsStmt:                   q.listPointsStmt,
+		listProblemStartsStmt:            q.listProblemStartsStmt,
+		listScheduleOverridesStmt:        q.listScheduleOverridesStmt,
+		listSubmissionsStmt:              q.listSubmissionsStmt,
+		listSubmitAttemptsStmt:           q.listSubmitAttemptsStmt,
+		listSubmittedProblemIDsStmt:      q.listSubmittedProblemIDsStmt,
+		listTeamAndMembersStmt:           q.listTeamAndMembersStmt,
+		listTeamMembersStmt:              q.listTeamMembersStmt,
+		listTeamsStmt:                    q.listTeamsStmt,
+		listVoidedProblemsStmt:           q.listVoidedProblemsStmt,
+		problemPointsByTeamStmt:          q.problemPointsByTeamStmt,
+		problemStartTimeStmt:             q.problemStartTimeStmt,
+		recordSubmissionStmt:             q.recordSubmissionStmt,
+		removePointsByReasonStmt:         q.removePointsByReasonStmt,
+		removePointsByTimeStmt:           q.removePointsByTimeStmt,
+		renameFirstSolvesProblemIDStmt:   q.renameFirstSolvesProblemIDStmt,
+		renamePointsProblemIDStmt:        q.renamePointsProblemIDStmt,
+		renameProblemStartsProblemIDStmt: q.renameProblemStartsProblemIDStmt,
+		renameSubmissionsProblemIDStmt:   q.renameSubmissionsProblemIDStmt,
+		renameVoidedProblemIDStmt:        q.renameVoidedProblemIDStmt,
+		resetLeaderboardRevealStmt:       q.resetLeaderboardRevealStmt,
+		setHackathonSubmissionStmt:       q.setHackathonSubmissionStmt,
+		setHackathonWinnerStmt:           q.setHackathonWinnerStmt,
+		setLeaderboardRevealStmt:         q.setLeaderboardRevealStmt,
+		startProblemStmt:                 q.startProblemStmt,
+		teamDivisionStmt:                 q.teamDivisionStmt,
+		teamInviteCodeStmt:               q.teamInviteCodeStmt,
+		teamPointsHistoryStmt:            q.teamPointsHistoryStmt,
+		voidProblemStmt:                  q.voidProblemStmt,
 	}
 }
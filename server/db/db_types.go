@@ -8,6 +8,16 @@ import (
 
 type DateTime time.Time
 
+// dateTimeFormat is the format of CURRENT_TIMESTAMP. DateTimes are stored in
+// the same format so that they sort correctly against column defaults.
+const dateTimeFormat = "2006-01-02 15:04:05"
+
+// NewDateTime converts a time.Time to a DateTime. The time is truncated to
+// whole seconds, which is the precision it is stored with.
+func NewDateTime(t time.Time) DateTime {
+	return DateTime(t.UTC().Truncate(time.Second))
+}
+
 // Time converts a DateTime to a time.Time.
 func (d DateTime) Time() time.Time {
 	return time.Time(d)
@@ -19,7 +29,7 @@ func (d *DateTime) Scan(src any) error {
 		*d = DateTime(src)
 		return nil
 	case string:
-		t, err := time.Parse("2006-01-02 15:04:05", src)
+		t, err := time.Parse(dateTimeFormat, src)
 		if err != nil {
 			return fmt.Errorf("parsing time: %w", err)
 		}
@@ -31,5 +41,5 @@ func (d *DateTime) Scan(src any) error {
 }
 
 func (d DateTime) Value() (driver.Value, error) {
-	return time.Time(d), nil
+	return time.Time(d).UTC().Format(dateTimeFormat), nil
 }
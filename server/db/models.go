@@ -8,6 +8,25 @@ import (
 	"database/sql"
 )
 
+type AuditLog struct {
+	ID        int64
+	CreatedAt DateTime
+	Actor     string
+	Action    string
+	Arguments string
+	Before    sql.NullString
+	After     sql.NullString
+}
+
+type FirstSolve struct {
+	Division  string
+	ProblemID string
+	Rank      int64
+	TeamName  string
+	SolvedAt  DateTime
+	Bonus     float64
+}
+
 type HackathonSubmission struct {
 	TeamName           string
 	SubmittedAt        DateTime
@@ -17,11 +36,35 @@ type HackathonSubmission struct {
 	WonRank            sql.NullInt64
 }
 
+type LeaderboardReveal struct {
+	Division  string
+	Revealed  int64
+	UpdatedAt DateTime
+}
+
+type ProblemStart struct {
+	TeamName  string
+	ProblemID string
+	StartedAt DateTime
+	StartedBy string
+}
+
+type ScheduleOverride struct {
+	ID           int64
+	CreatedAt    DateTime
+	Action       string
+	ProblemIndex sql.NullInt64
+	DelaySeconds sql.NullInt64
+	Reason       string
+	Division     string
+}
+
 type Team struct {
 	TeamName         string
 	CreatedAt        DateTime
 	InviteCode       string
 	AcceptingMembers bool
+	Division         string
 }
 
 type TeamMember struct {
@@ -32,16 +75,33 @@ type TeamMember struct {
 }
 
 type TeamPoint struct {
-	TeamName string
-	AddedAt  DateTime
-	Points   float64
-	Reason   string
+	ID           int64
+	TeamName     string
+	AddedAt      DateTime
+	Points       float64
+	Reason       string
+	ProblemID    sql.NullString
+	Source       string
+	Part         sql.NullInt64
+	SubmissionID sql.NullInt64
+	Actor        sql.NullString
 }
 
 type TeamSubmitAttempt struct {
+	ID          int64
 	TeamName    string
 	ProblemID   string
 	SubmittedAt DateTime
 	Correct     bool
 	SubmittedBy sql.NullString
+	Practice    bool
+	Answer      sql.NullInt64
+}
+
+type VoidedProblem struct {
+	Division  string
+	ProblemID string
+	VoidedAt  DateTime
+	Credit    float64
+	Reason    string
 }
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "embed"
+
+	"libdb.so/lazymigrate"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed sql_schema_postgres.sql
+var postgresSchema string
+
+// postgresLockID is the key of the advisory lock that serializes migrations
+// and transactions across all server replicas.
+const postgresLockID = 0x6d6d32303234 // "mm2024"
+
+// isPostgresURL returns true if the database URL points to PostgreSQL.
+func isPostgresURL(url string) bool {
+	return strings.HasPrefix(url, "postgres://") || strings.HasPrefix(url, "postgresql://")
+}
+
+func openPostgres(url string) (*Database, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migratePostgres(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return &Database{
+		Queries: New(postgresDBTX{db}),
+		db:      db,
+		dialect: PostgreSQL,
+	}, nil
+}
+
+// migratePostgres applies the versions of the PostgreSQL schema that haven't
+// been applied yet, in the same way that lazymigrate does for SQLite. The
+// applied version is kept in the schema_version table instead of the
+// user_version pragma.
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Replicas that start at the same time must not migrate concurrently.
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresLockID); err != nil {
+		return fmt.Errorf("cannot lock database: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)"); err != nil {
+		return fmt.Errorf("cannot create schema_version: %w", err)
+	}
+
+	var v int
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&v); err != nil {
+		return fmt.Errorf("cannot get schema version: %w", err)
+	}
+
+	versions := lazymigrate.NewSchema(postgresSchema).Versions()
+	if v >= len(versions) {
+		return nil
+	}
+
+	for i := v; i < len(versions); i++ {
+		if _, err := tx.ExecContext(ctx, versions[i]); err != nil {
+			return fmt.Errorf("cannot apply migration %d (from 0th): %w", i, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_version"); err != nil {
+		return fmt.Errorf("cannot clear schema version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES ($1)", len(versions)); err != nil {
+		return fmt.Errorf("cannot set schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// postgresDBTX runs the queries generated by sqlc, which are written for
+// SQLite, on PostgreSQL. The queries stick to the SQL that both databases
+// understand, except for their ? placeholders, which are numbered here.
+type postgresDBTX struct {
+	DBTX
+}
+
+func (p postgresDBTX) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return p.DBTX.ExecContext(ctx, rebindPostgres(query), postgresArgs(args)...)
+}
+
+func (p postgresDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.DBTX.PrepareContext(ctx, rebindPostgres(query))
+}
+
+func (p postgresDBTX) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return p.DBTX.QueryContext(ctx, rebindPostgres(query), postgresArgs(args)...)
+}
+
+func (p postgresDBTX) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return p.DBTX.QueryRowContext(ctx, rebindPostgres(query), postgresArgs(args)...)
+}
+
+// rebindPostgres replaces the ? placeholders of the query with $1, $2 and so
+// on. Question marks in string literals, quoted identifiers and comments are
+// left alone.
+func rebindPostgres(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'', '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end == -1 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case '-':
+			if !strings.HasPrefix(query[i:], "--") {
+				b.WriteByte(c)
+				continue
+			}
+			end := strings.IndexByte(query[i:], '\n')
+			if end == -1 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+		case '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// postgresArgs converts DateTimes to time.Time, which pgx stores as TIMESTAMP
+// directly, rather than as the text that SQLite stores.
+func postgresArgs(args []any) []any {
+	for i, arg := range args {
+		if t, ok := arg.(DateTime); ok {
+			args[i] = time.Time(t).UTC()
+		}
+	}
+	return args
+}
+
+// identityTables are the tables with a generated id column.
+var identityTables = []string{"team_submit_attempts", "team_points", "schedule_overrides", "audit_log"}
+
+// RestartIDSequences makes the generated ids of new rows continue after the
+// highest id of each table. It needs to be called after rows are inserted
+// with explicit ids, which PostgreSQL doesn't account for, unlike SQLite.
+func (db *Database) RestartIDSequences(ctx context.Context) error {
+	if db.dialect != PostgreSQL {
+		return nil
+	}
+	for _, table := range identityTables {
+		query := fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %[1]s",
+			table)
+		if _, err := db.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to restart the id sequence of %s: %w", table, err)
+		}
+	}
+	return nil
+}
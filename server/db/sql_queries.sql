@@ -1,8 +1,8 @@
 -- name: CreateTeam :one
-INSERT INTO teams (team_name, invite_code) VALUES (?, ?) RETURNING *;
+INSERT INTO teams (team_name, invite_code, division, created_at) VALUES (?, ?, ?, ?) RETURNING *;
 
 -- name: JoinTeam :one
-INSERT INTO team_members (team_name, user_name, is_leader) VALUES (?, ?, ?) RETURNING *;
+INSERT INTO team_members (team_name, user_name, is_leader, joined_at) VALUES (?, ?, ?, ?) RETURNING *;
 
 -- name: LeaveTeam :exec
 DELETE FROM team_members WHERE team_name = ? AND user_name = ?;
@@ -11,7 +11,7 @@ DELETE FROM team_members WHERE team_name = ? AND user_name = ?;
 SELECT is_leader FROM team_members WHERE team_name = ? AND user_name = ?;
 
 -- name: RecordSubmission :one
-INSERT INTO team_submit_attempts (team_name, submitted_by, problem_id, correct) VALUES (?, ?, ?, ?) RETURNING *;
+INSERT INTO team_submit_attempts (team_name, submitted_by, problem_id, correct, practice, submitted_at, answer) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: HasSolved :one
 SELECT COUNT(*) FROM team_submit_attempts WHERE team_name = ? AND problem_id = ? AND correct = TRUE;
@@ -23,12 +23,22 @@ SELECT * FROM team_submit_attempts WHERE team_name = ? AND problem_id = ?
 -- name: ListAllCorrectSubmissions :many
 SELECT *
 	FROM team_submit_attempts
-	WHERE correct = TRUE
+	WHERE correct = TRUE AND practice = FALSE
 	ORDER BY submitted_at ASC;
 
+-- name: ListAllSubmissions :many
+SELECT *
+	FROM team_submit_attempts
+	WHERE practice = FALSE
+	ORDER BY submitted_at ASC, id ASC;
+
 -- name: CountIncorrectSubmissions :one
 SELECT COUNT(*) FROM team_submit_attempts WHERE team_name = ? AND problem_id = ? AND correct = FALSE;
 
+-- name: HasSubmittedWrongAnswer :one
+SELECT COUNT(*) FROM team_submit_attempts
+	WHERE team_name = ? AND problem_id = ? AND answer = ? AND correct = FALSE;
+
 -- name: LastSubmissionTime :one
 SELECT submitted_at FROM team_submit_attempts
 	WHERE team_name = ? AND problem_id = ?
@@ -36,25 +46,15 @@ SELECT submitted_at FROM team_submit_attempts
 	LIMIT 1;
 
 -- name: AddPoints :one
-INSERT INTO team_points (team_name, points, reason) VALUES (?, ?, ?) RETURNING *;
+INSERT INTO team_points (team_name, points, reason, source, problem_id, part, submission_id, actor, added_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: RemovePointsByReason :many
-DELETE FROM team_points WHERE team_name = ? AND reason = ? RETURNING *;
+DELETE FROM team_points WHERE team_name = ? AND source = ? AND reason = ? RETURNING *;
 
 -- name: RemovePointsByTime :one
 DELETE FROM team_points WHERE team_name = ? AND added_at = ? RETURNING *;
 
--- name: TeamPointsTotal :many
-SELECT team_name, SUM(points) AS points
-	FROM team_points
-	GROUP BY team_name
-	ORDER BY COALESCE(SUM(points), 0) DESC;
-
--- name: TeamPointsEach :many
-SELECT team_name, reason, SUM(points) AS points
-	FROM team_points
-	GROUP BY team_name, reason;
-
 -- name: TeamPointsHistory :many
 SELECT *
 	FROM (
@@ -67,16 +67,19 @@ SELECT *
 	ORDER BY added_at ASC;
 
 -- name: ListTeams :many
-SELECT team_name, created_at, accepting_members FROM teams;
+SELECT team_name, created_at, accepting_members, division FROM teams;
 
 -- name: ListTeamAndMembers :many
 SELECT team_name, user_name FROM team_members ORDER BY joined_at ASC;
 
 -- name: FindTeamWithInviteCode :one
-SELECT team_name, created_at, accepting_members FROM teams WHERE invite_code = ? AND accepting_members = TRUE;
+SELECT team_name, created_at, accepting_members, division FROM teams WHERE invite_code = ? AND accepting_members = TRUE;
 
 -- name: FindTeam :one
-SELECT team_name, created_at, accepting_members FROM teams WHERE team_name = ?;
+SELECT team_name, created_at, accepting_members, division FROM teams WHERE team_name = ?;
+
+-- name: TeamDivision :one
+SELECT division FROM teams WHERE team_name = ?;
 
 -- name: TeamInviteCode :one
 SELECT invite_code FROM teams WHERE team_name = ?;
@@ -88,7 +91,13 @@ SELECT * FROM team_members WHERE team_name = ? ORDER BY joined_at ASC;
 DELETE FROM teams WHERE team_name = ? RETURNING *;
 
 -- name: SetHackathonSubmission :exec
-REPLACE INTO hackathon_submissions (team_name, project_url, project_description, category) VALUES (?, ?, ?, ?);
+INSERT INTO hackathon_submissions (team_name, project_url, project_description, category, submitted_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (team_name) DO UPDATE SET
+		submitted_at = excluded.submitted_at,
+		project_url = excluded.project_url,
+		project_description = excluded.project_description,
+		category = excluded.category,
+		won_rank = NULL;
 
 -- name: SetHackathonWinner :exec
 UPDATE hackathon_submissions SET won_rank = ? WHERE team_name = ?;
@@ -101,3 +110,120 @@ SELECT * FROM hackathon_submissions WHERE team_name = ?;
 
 -- name: HackathonWinners :many
 SELECT * FROM hackathon_submissions WHERE won_rank IS NOT NULL ORDER BY won_rank ASC;
+
+-- name: AddScheduleOverride :one
+INSERT INTO schedule_overrides (division, action, problem_index, delay_seconds, reason, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: ListScheduleOverrides :many
+SELECT * FROM schedule_overrides ORDER BY id ASC;
+
+-- name: ProblemPointsByTeam :many
+SELECT team_points.team_name, team_points.source, SUM(team_points.points) AS points
+	FROM team_points
+	JOIN teams ON teams.team_name = team_points.team_name
+	WHERE team_points.problem_id = ? AND teams.division = ?
+	GROUP BY team_points.team_name, team_points.source;
+
+-- name: CountSolves :one
+SELECT COUNT(DISTINCT team_submit_attempts.team_name)
+	FROM team_submit_attempts
+	JOIN teams ON teams.team_name = team_submit_attempts.team_name
+	WHERE team_submit_attempts.problem_id = ?
+		AND team_submit_attempts.correct = TRUE
+		AND team_submit_attempts.practice = FALSE
+		AND teams.division = ?;
+
+-- name: VoidProblem :one
+INSERT INTO voided_problems (division, problem_id, credit, reason, voided_at) VALUES (?, ?, ?, ?, ?) RETURNING *;
+
+-- name: ListVoidedProblems :many
+SELECT * FROM voided_problems ORDER BY voided_at ASC;
+
+-- name: ListSubmittedProblemIDs :many
+SELECT DISTINCT problem_id FROM team_submit_attempts;
+
+-- name: RenameSubmissionsProblemID :execrows
+UPDATE team_submit_attempts SET problem_id = sqlc.arg(new_problem_id) WHERE problem_id = sqlc.arg(old_problem_id);
+
+-- name: RenamePointsProblemID :execrows
+UPDATE team_points SET problem_id = sqlc.arg(new_problem_id) WHERE problem_id = sqlc.arg(old_problem_id);
+
+-- name: RenameVoidedProblemID :execrows
+UPDATE voided_problems SET problem_id = sqlc.arg(new_problem_id) WHERE problem_id = sqlc.arg(old_problem_id);
+
+-- name: CountFirstSolves :one
+SELECT COUNT(*) FROM first_solves WHERE division = ? AND problem_id = ?;
+
+-- name: AddFirstSolve :one
+INSERT INTO first_solves (division, problem_id, rank, team_name, solved_at, bonus) VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (division, problem_id, rank) DO NOTHING
+	RETURNING *;
+
+-- name: ListFirstSolves :many
+SELECT * FROM first_solves ORDER BY solved_at ASC, problem_id ASC, rank ASC;
+
+-- name: RenameFirstSolvesProblemID :execrows
+UPDATE first_solves SET problem_id = sqlc.arg(new_problem_id) WHERE problem_id = sqlc.arg(old_problem_id);
+
+-- name: StartProblem :exec
+INSERT INTO problem_starts (team_name, problem_id, started_at, started_by) VALUES (?, ?, ?, ?)
+	ON CONFLICT DO NOTHING;
+
+-- name: ProblemStartTime :one
+SELECT started_at FROM problem_starts WHERE team_name = ? AND problem_id = ?;
+
+-- name: RenameProblemStartsProblemID :execrows
+UPDATE problem_starts SET problem_id = sqlc.arg(new_problem_id) WHERE problem_id = sqlc.arg(old_problem_id);
+
+-- name: ListPoints :many
+SELECT * FROM team_points ORDER BY id ASC;
+
+-- name: GetLeaderboardReveal :one
+SELECT * FROM leaderboard_reveals WHERE division = ?;
+
+-- name: SetLeaderboardReveal :one
+INSERT INTO leaderboard_reveals (division, revealed, updated_at) VALUES (?, ?, ?)
+	ON CONFLICT (division) DO UPDATE SET revealed = excluded.revealed, updated_at = excluded.updated_at
+	RETURNING *;
+
+-- name: ResetLeaderboardReveal :exec
+DELETE FROM leaderboard_reveals WHERE division = ?;
+
+-- name: ListAllTeams :many
+SELECT * FROM teams ORDER BY created_at ASC, team_name ASC;
+
+-- name: ListAllTeamMembers :many
+SELECT * FROM team_members ORDER BY joined_at ASC, user_name ASC;
+
+-- name: ListSubmitAttempts :many
+SELECT * FROM team_submit_attempts ORDER BY id ASC;
+
+-- name: ListProblemStarts :many
+SELECT * FROM problem_starts ORDER BY started_at ASC, team_name ASC, problem_id ASC;
+
+-- name: ListLeaderboardReveals :many
+SELECT * FROM leaderboard_reveals ORDER BY division ASC;
+
+-- name: ImportTeam :exec
+INSERT INTO teams (team_name, created_at, invite_code, accepting_members, division) VALUES (?, ?, ?, ?, ?);
+
+-- name: ImportSubmitAttempt :exec
+INSERT INTO team_submit_attempts (id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportPoints :exec
+INSERT INTO team_points (id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportHackathonSubmission :exec
+INSERT INTO hackathon_submissions (team_name, submitted_at, project_url, project_description, category, won_rank) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ImportScheduleOverride :exec
+INSERT INTO schedule_overrides (id, created_at, action, problem_index, delay_seconds, reason, division) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: AddAuditLog :one
+INSERT INTO audit_log (actor, action, arguments, before, after, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: ListAuditLog :many
+SELECT * FROM audit_log ORDER BY id ASC;
+
+-- name: ImportAuditLog :exec
+INSERT INTO audit_log (id, created_at, actor, action, arguments, before, after) VALUES (?, ?, ?, ?, ?, ?, ?);
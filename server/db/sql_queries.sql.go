@@ -10,28 +10,173 @@ import (
 	"database/sql"
 )
 
+const addAuditLog = `-- name: AddAuditLog :one
+INSERT INTO audit_log (actor, action, arguments, before, after, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, created_at, actor, action, arguments, before, after
+`
+
+type AddAuditLogParams struct {
+	Actor     string
+	Action    string
+	Arguments string
+	Before    sql.NullString
+	After     sql.NullString
+	CreatedAt DateTime
+}
+
+func (q *Queries) AddAuditLog(ctx context.Context, arg AddAuditLogParams) (AuditLog, error) {
+	row := q.queryRow(ctx, q.addAuditLogStmt, addAuditLog,
+		arg.Actor,
+		arg.Action,
+		arg.Arguments,
+		arg.Before,
+		arg.After,
+		arg.CreatedAt,
+	)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Actor,
+		&i.Action,
+		&i.Arguments,
+		&i.Before,
+		&i.After,
+	)
+	return i, err
+}
+
+const addFirstSolve = `-- name: AddFirstSolve :one
+INSERT INTO first_solves (division, problem_id, rank, team_name, solved_at, bonus) VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (division, problem_id, rank) DO NOTHING
+	RETURNING division, problem_id, rank, team_name, solved_at, bonus
+`
+
+type AddFirstSolveParams struct {
+	Division  string
+	ProblemID string
+	Rank      int64
+	TeamName  string
+	SolvedAt  DateTime
+	Bonus     float64
+}
+
+func (q *Queries) AddFirstSolve(ctx context.Context, arg AddFirstSolveParams) (FirstSolve, error) {
+	row := q.queryRow(ctx, q.addFirstSolveStmt, addFirstSolve,
+		arg.Division,
+		arg.ProblemID,
+		arg.Rank,
+		arg.TeamName,
+		arg.SolvedAt,
+		arg.Bonus,
+	)
+	var i FirstSolve
+	err := row.Scan(
+		&i.Division,
+		&i.ProblemID,
+		&i.Rank,
+		&i.TeamName,
+		&i.SolvedAt,
+		&i.Bonus,
+	)
+	return i, err
+}
+
 const addPoints = `-- name: AddPoints :one
-INSERT INTO team_points (team_name, points, reason) VALUES (?, ?, ?) RETURNING team_name, added_at, points, reason
+INSERT INTO team_points (team_name, points, reason, source, problem_id, part, submission_id, actor, added_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor
 `
 
 type AddPointsParams struct {
-	TeamName string
-	Points   float64
-	Reason   string
+	TeamName     string
+	Points       float64
+	Reason       string
+	Source       string
+	ProblemID    sql.NullString
+	Part         sql.NullInt64
+	SubmissionID sql.NullInt64
+	Actor        sql.NullString
+	AddedAt      DateTime
 }
 
 func (q *Queries) AddPoints(ctx context.Context, arg AddPointsParams) (TeamPoint, error) {
-	row := q.queryRow(ctx, q.addPointsStmt, addPoints, arg.TeamName, arg.Points, arg.Reason)
+	row := q.queryRow(ctx, q.addPointsStmt, addPoints,
+		arg.TeamName,
+		arg.Points,
+		arg.Reason,
+		arg.Source,
+		arg.ProblemID,
+		arg.Part,
+		arg.SubmissionID,
+		arg.Actor,
+		arg.AddedAt,
+	)
 	var i TeamPoint
 	err := row.Scan(
+		&i.ID,
 		&i.TeamName,
 		&i.AddedAt,
 		&i.Points,
 		&i.Reason,
+		&i.ProblemID,
+		&i.Source,
+		&i.Part,
+		&i.SubmissionID,
+		&i.Actor,
+	)
+	return i, err
+}
+
+const addScheduleOverride = `-- name: AddScheduleOverride :one
+INSERT INTO schedule_overrides (division, action, problem_index, delay_seconds, reason, created_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, created_at, action, problem_index, delay_seconds, reason, division
+`
+
+type AddScheduleOverrideParams struct {
+	Division     string
+	Action       string
+	ProblemIndex sql.NullInt64
+	DelaySeconds sql.NullInt64
+	Reason       string
+	CreatedAt    DateTime
+}
+
+func (q *Queries) AddScheduleOverride(ctx context.Context, arg AddScheduleOverrideParams) (ScheduleOverride, error) {
+	row := q.queryRow(ctx, q.addScheduleOverrideStmt, addScheduleOverride,
+		arg.Division,
+		arg.Action,
+		arg.ProblemIndex,
+		arg.DelaySeconds,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+	var i ScheduleOverride
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Action,
+		&i.ProblemIndex,
+		&i.DelaySeconds,
+		&i.Reason,
+		&i.Division,
 	)
 	return i, err
 }
 
+const countFirstSolves = `-- name: CountFirstSolves :one
+SELECT COUNT(*) FROM first_solves WHERE division = ? AND problem_id = ?
+`
+
+type CountFirstSolvesParams struct {
+	Division  string
+	ProblemID string
+}
+
+func (q *Queries) CountFirstSolves(ctx context.Context, arg CountFirstSolvesParams) (int64, error) {
+	row := q.queryRow(ctx, q.countFirstSolvesStmt, countFirstSolves, arg.Division, arg.ProblemID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countIncorrectSubmissions = `-- name: CountIncorrectSubmissions :one
 SELECT COUNT(*) FROM team_submit_attempts WHERE team_name = ? AND problem_id = ? AND correct = FALSE
 `
@@ -48,29 +193,59 @@ func (q *Queries) CountIncorrectSubmissions(ctx context.Context, arg CountIncorr
 	return count, err
 }
 
+const countSolves = `-- name: CountSolves :one
+SELECT COUNT(DISTINCT team_submit_attempts.team_name)
+	FROM team_submit_attempts
+	JOIN teams ON teams.team_name = team_submit_attempts.team_name
+	WHERE team_submit_attempts.problem_id = ?
+		AND team_submit_attempts.correct = TRUE
+		AND team_submit_attempts.practice = FALSE
+		AND teams.division = ?
+`
+
+type CountSolvesParams struct {
+	ProblemID string
+	Division  string
+}
+
+func (q *Queries) CountSolves(ctx context.Context, arg CountSolvesParams) (int64, error) {
+	row := q.queryRow(ctx, q.countSolvesStmt, countSolves, arg.ProblemID, arg.Division)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTeam = `-- name: CreateTeam :one
-INSERT INTO teams (team_name, invite_code) VALUES (?, ?) RETURNING team_name, created_at, invite_code, accepting_members
+INSERT INTO teams (team_name, invite_code, division, created_at) VALUES (?, ?, ?, ?) RETURNING team_name, created_at, invite_code, accepting_members, division
 `
 
 type CreateTeamParams struct {
 	TeamName   string
 	InviteCode string
+	Division   string
+	CreatedAt  DateTime
 }
 
 func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error) {
-	row := q.queryRow(ctx, q.createTeamStmt, createTeam, arg.TeamName, arg.InviteCode)
+	row := q.queryRow(ctx, q.createTeamStmt, createTeam,
+		arg.TeamName,
+		arg.InviteCode,
+		arg.Division,
+		arg.CreatedAt,
+	)
 	var i Team
 	err := row.Scan(
 		&i.TeamName,
 		&i.CreatedAt,
 		&i.InviteCode,
 		&i.AcceptingMembers,
+		&i.Division,
 	)
 	return i, err
 }
 
 const dropTeam = `-- name: DropTeam :one
-DELETE FROM teams WHERE team_name = ? RETURNING team_name, created_at, invite_code, accepting_members
+DELETE FROM teams WHERE team_name = ? RETURNING team_name, created_at, invite_code, accepting_members, division
 `
 
 func (q *Queries) DropTeam(ctx context.Context, teamName string) (Team, error) {
@@ -81,41 +256,65 @@ func (q *Queries) DropTeam(ctx context.Context, teamName string) (Team, error) {
 		&i.CreatedAt,
 		&i.InviteCode,
 		&i.AcceptingMembers,
+		&i.Division,
 	)
 	return i, err
 }
 
 const findTeam = `-- name: FindTeam :one
-SELECT team_name, created_at, accepting_members FROM teams WHERE team_name = ?
+SELECT team_name, created_at, accepting_members, division FROM teams WHERE team_name = ?
 `
 
 type FindTeamRow struct {
 	TeamName         string
 	CreatedAt        DateTime
 	AcceptingMembers bool
+	Division         string
 }
 
 func (q *Queries) FindTeam(ctx context.Context, teamName string) (FindTeamRow, error) {
 	row := q.queryRow(ctx, q.findTeamStmt, findTeam, teamName)
 	var i FindTeamRow
-	err := row.Scan(&i.TeamName, &i.CreatedAt, &i.AcceptingMembers)
+	err := row.Scan(
+		&i.TeamName,
+		&i.CreatedAt,
+		&i.AcceptingMembers,
+		&i.Division,
+	)
 	return i, err
 }
 
 const findTeamWithInviteCode = `-- name: FindTeamWithInviteCode :one
-SELECT team_name, created_at, accepting_members FROM teams WHERE invite_code = ? AND accepting_members = TRUE
+SELECT team_name, created_at, accepting_members, division FROM teams WHERE invite_code = ? AND accepting_members = TRUE
 `
 
 type FindTeamWithInviteCodeRow struct {
 	TeamName         string
 	CreatedAt        DateTime
 	AcceptingMembers bool
+	Division         string
 }
 
 func (q *Queries) FindTeamWithInviteCode(ctx context.Context, inviteCode string) (FindTeamWithInviteCodeRow, error) {
 	row := q.queryRow(ctx, q.findTeamWithInviteCodeStmt, findTeamWithInviteCode, inviteCode)
 	var i FindTeamWithInviteCodeRow
-	err := row.Scan(&i.TeamName, &i.CreatedAt, &i.AcceptingMembers)
+	err := row.Scan(
+		&i.TeamName,
+		&i.CreatedAt,
+		&i.AcceptingMembers,
+		&i.Division,
+	)
+	return i, err
+}
+
+const getLeaderboardReveal = `-- name: GetLeaderboardReveal :one
+SELECT division, revealed, updated_at FROM leaderboard_reveals WHERE division = ?
+`
+
+func (q *Queries) GetLeaderboardReveal(ctx context.Context, division string) (LeaderboardReveal, error) {
+	row := q.queryRow(ctx, q.getLeaderboardRevealStmt, getLeaderboardReveal, division)
+	var i LeaderboardReveal
+	err := row.Scan(&i.Division, &i.Revealed, &i.UpdatedAt)
 	return i, err
 }
 
@@ -221,6 +420,188 @@ func (q *Queries) HasSolved(ctx context.Context, arg HasSolvedParams) (int64, er
 	return count, err
 }
 
+const hasSubmittedWrongAnswer = `-- name: HasSubmittedWrongAnswer :one
+SELECT COUNT(*) FROM team_submit_attempts
+	WHERE team_name = ? AND problem_id = ? AND answer = ? AND correct = FALSE
+`
+
+type HasSubmittedWrongAnswerParams struct {
+	TeamName  string
+	ProblemID string
+	Answer    sql.NullInt64
+}
+
+func (q *Queries) HasSubmittedWrongAnswer(ctx context.Context, arg HasSubmittedWrongAnswerParams) (int64, error) {
+	row := q.queryRow(ctx, q.hasSubmittedWrongAnswerStmt, hasSubmittedWrongAnswer, arg.TeamName, arg.ProblemID, arg.Answer)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const importAuditLog = `-- name: ImportAuditLog :exec
+INSERT INTO audit_log (id, created_at, actor, action, arguments, before, after) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type ImportAuditLogParams struct {
+	ID        int64
+	CreatedAt DateTime
+	Actor     string
+	Action    string
+	Arguments string
+	Before    sql.NullString
+	After     sql.NullString
+}
+
+func (q *Queries) ImportAuditLog(ctx context.Context, arg ImportAuditLogParams) error {
+	_, err := q.exec(ctx, q.importAuditLogStmt, importAuditLog,
+		arg.ID,
+		arg.CreatedAt,
+		arg.Actor,
+		arg.Action,
+		arg.Arguments,
+		arg.Before,
+		arg.After,
+	)
+	return err
+}
+
+const importHackathonSubmission = `-- name: ImportHackathonSubmission :exec
+INSERT INTO hackathon_submissions (team_name, submitted_at, project_url, project_description, category, won_rank) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type ImportHackathonSubmissionParams struct {
+	TeamName           string
+	SubmittedAt        DateTime
+	ProjectUrl         string
+	ProjectDescription sql.NullString
+	Category           string
+	WonRank            sql.NullInt64
+}
+
+func (q *Queries) ImportHackathonSubmission(ctx context.Context, arg ImportHackathonSubmissionParams) error {
+	_, err := q.exec(ctx, q.importHackathonSubmissionStmt, importHackathonSubmission,
+		arg.TeamName,
+		arg.SubmittedAt,
+		arg.ProjectUrl,
+		arg.ProjectDescription,
+		arg.Category,
+		arg.WonRank,
+	)
+	return err
+}
+
+const importPoints = `-- name: ImportPoints :exec
+INSERT INTO team_points (id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type ImportPointsParams struct {
+	ID           int64
+	TeamName     string
+	AddedAt      DateTime
+	Points       float64
+	Reason       string
+	ProblemID    sql.NullString
+	Source       string
+	Part         sql.NullInt64
+	SubmissionID sql.NullInt64
+	Actor        sql.NullString
+}
+
+func (q *Queries) ImportPoints(ctx context.Context, arg ImportPointsParams) error {
+	_, err := q.exec(ctx, q.importPointsStmt, importPoints,
+		arg.ID,
+		arg.TeamName,
+		arg.AddedAt,
+		arg.Points,
+		arg.Reason,
+		arg.ProblemID,
+		arg.Source,
+		arg.Part,
+		arg.SubmissionID,
+		arg.Actor,
+	)
+	return err
+}
+
+const importScheduleOverride = `-- name: ImportScheduleOverride :exec
+INSERT INTO schedule_overrides (id, created_at, action, problem_index, delay_seconds, reason, division) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type ImportScheduleOverrideParams struct {
+	ID           int64
+	CreatedAt    DateTime
+	Action       string
+	ProblemIndex sql.NullInt64
+	DelaySeconds sql.NullInt64
+	Reason       string
+	Division     string
+}
+
+func (q *Queries) ImportScheduleOverride(ctx context.Context, arg ImportScheduleOverrideParams) error {
+	_, err := q.exec(ctx, q.importScheduleOverrideStmt, importScheduleOverride,
+		arg.ID,
+		arg.CreatedAt,
+		arg.Action,
+		arg.ProblemIndex,
+		arg.DelaySeconds,
+		arg.Reason,
+		arg.Division,
+	)
+	return err
+}
+
+const importSubmitAttempt = `-- name: ImportSubmitAttempt :exec
+INSERT INTO team_submit_attempts (id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type ImportSubmitAttemptParams struct {
+	ID          int64
+	TeamName    string
+	ProblemID   string
+	SubmittedAt DateTime
+	Correct     bool
+	SubmittedBy sql.NullString
+	Practice    bool
+	Answer      sql.NullInt64
+}
+
+func (q *Queries) ImportSubmitAttempt(ctx context.Context, arg ImportSubmitAttemptParams) error {
+	_, err := q.exec(ctx, q.importSubmitAttemptStmt, importSubmitAttempt,
+		arg.ID,
+		arg.TeamName,
+		arg.ProblemID,
+		arg.SubmittedAt,
+		arg.Correct,
+		arg.SubmittedBy,
+		arg.Practice,
+		arg.Answer,
+	)
+	return err
+}
+
+const importTeam = `-- name: ImportTeam :exec
+INSERT INTO teams (team_name, created_at, invite_code, accepting_members, division) VALUES (?, ?, ?, ?, ?)
+`
+
+type ImportTeamParams struct {
+	TeamName         string
+	CreatedAt        DateTime
+	InviteCode       string
+	AcceptingMembers bool
+	Division         string
+}
+
+func (q *Queries) ImportTeam(ctx context.Context, arg ImportTeamParams) error {
+	_, err := q.exec(ctx, q.importTeamStmt, importTeam,
+		arg.TeamName,
+		arg.CreatedAt,
+		arg.InviteCode,
+		arg.AcceptingMembers,
+		arg.Division,
+	)
+	return err
+}
+
 const isLeader = `-- name: IsLeader :one
 SELECT is_leader FROM team_members WHERE team_name = ? AND user_name = ?
 `
@@ -238,17 +619,23 @@ func (q *Queries) IsLeader(ctx context.Context, arg IsLeaderParams) (bool, error
 }
 
 const joinTeam = `-- name: JoinTeam :one
-INSERT INTO team_members (team_name, user_name, is_leader) VALUES (?, ?, ?) RETURNING team_name, user_name, joined_at, is_leader
+INSERT INTO team_members (team_name, user_name, is_leader, joined_at) VALUES (?, ?, ?, ?) RETURNING team_name, user_name, joined_at, is_leader
 `
 
 type JoinTeamParams struct {
 	TeamName string
 	Username string
 	IsLeader bool
+	JoinedAt DateTime
 }
 
 func (q *Queries) JoinTeam(ctx context.Context, arg JoinTeamParams) (TeamMember, error) {
-	row := q.queryRow(ctx, q.joinTeamStmt, joinTeam, arg.TeamName, arg.Username, arg.IsLeader)
+	row := q.queryRow(ctx, q.joinTeamStmt, joinTeam,
+		arg.TeamName,
+		arg.Username,
+		arg.IsLeader,
+		arg.JoinedAt,
+	)
 	var i TeamMember
 	err := row.Scan(
 		&i.TeamName,
@@ -293,9 +680,9 @@ func (q *Queries) LeaveTeam(ctx context.Context, arg LeaveTeamParams) error {
 }
 
 const listAllCorrectSubmissions = `-- name: ListAllCorrectSubmissions :many
-SELECT team_name, problem_id, submitted_at, correct, submitted_by
+SELECT id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer
 	FROM team_submit_attempts
-	WHERE correct = TRUE
+	WHERE correct = TRUE AND practice = FALSE
 	ORDER BY submitted_at ASC
 `
 
@@ -309,11 +696,14 @@ func (q *Queries) ListAllCorrectSubmissions(ctx context.Context) ([]TeamSubmitAt
 	for rows.Next() {
 		var i TeamSubmitAttempt
 		if err := rows.Scan(
+			&i.ID,
 			&i.TeamName,
 			&i.ProblemID,
 			&i.SubmittedAt,
 			&i.Correct,
 			&i.SubmittedBy,
+			&i.Practice,
+			&i.Answer,
 		); err != nil {
 			return nil, err
 		}
@@ -328,18 +718,15 @@ func (q *Queries) ListAllCorrectSubmissions(ctx context.Context) ([]TeamSubmitAt
 	return items, nil
 }
 
-const listSubmissions = `-- name: ListSubmissions :many
-SELECT team_name, problem_id, submitted_at, correct, submitted_by FROM team_submit_attempts WHERE team_name = ? AND problem_id = ?
-	ORDER BY submitted_at ASC
+const listAllSubmissions = `-- name: ListAllSubmissions :many
+SELECT id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer
+	FROM team_submit_attempts
+	WHERE practice = FALSE
+	ORDER BY submitted_at ASC, id ASC
 `
 
-type ListSubmissionsParams struct {
-	TeamName  string
-	ProblemID string
-}
-
-func (q *Queries) ListSubmissions(ctx context.Context, arg ListSubmissionsParams) ([]TeamSubmitAttempt, error) {
-	rows, err := q.query(ctx, q.listSubmissionsStmt, listSubmissions, arg.TeamName, arg.ProblemID)
+func (q *Queries) ListAllSubmissions(ctx context.Context) ([]TeamSubmitAttempt, error) {
+	rows, err := q.query(ctx, q.listAllSubmissionsStmt, listAllSubmissions)
 	if err != nil {
 		return nil, err
 	}
@@ -348,11 +735,14 @@ func (q *Queries) ListSubmissions(ctx context.Context, arg ListSubmissionsParams
 	for rows.Next() {
 		var i TeamSubmitAttempt
 		if err := rows.Scan(
+			&i.ID,
 			&i.TeamName,
 			&i.ProblemID,
 			&i.SubmittedAt,
 			&i.Correct,
 			&i.SubmittedBy,
+			&i.Practice,
+			&i.Answer,
 		); err != nil {
 			return nil, err
 		}
@@ -367,25 +757,25 @@ func (q *Queries) ListSubmissions(ctx context.Context, arg ListSubmissionsParams
 	return items, nil
 }
 
-const listTeamAndMembers = `-- name: ListTeamAndMembers :many
-SELECT team_name, user_name FROM team_members ORDER BY joined_at ASC
+const listAllTeamMembers = `-- name: ListAllTeamMembers :many
+SELECT team_name, user_name, joined_at, is_leader FROM team_members ORDER BY joined_at ASC, user_name ASC
 `
 
-type ListTeamAndMembersRow struct {
-	TeamName string
-	Username string
-}
-
-func (q *Queries) ListTeamAndMembers(ctx context.Context) ([]ListTeamAndMembersRow, error) {
-	rows, err := q.query(ctx, q.listTeamAndMembersStmt, listTeamAndMembers)
+func (q *Queries) ListAllTeamMembers(ctx context.Context) ([]TeamMember, error) {
+	rows, err := q.query(ctx, q.listAllTeamMembersStmt, listAllTeamMembers)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListTeamAndMembersRow
+	var items []TeamMember
 	for rows.Next() {
-		var i ListTeamAndMembersRow
-		if err := rows.Scan(&i.TeamName, &i.Username); err != nil {
+		var i TeamMember
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.Username,
+			&i.JoinedAt,
+			&i.IsLeader,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -399,24 +789,25 @@ func (q *Queries) ListTeamAndMembers(ctx context.Context) ([]ListTeamAndMembersR
 	return items, nil
 }
 
-const listTeamMembers = `-- name: ListTeamMembers :many
-SELECT team_name, user_name, joined_at, is_leader FROM team_members WHERE team_name = ? ORDER BY joined_at ASC
+const listAllTeams = `-- name: ListAllTeams :many
+SELECT team_name, created_at, invite_code, accepting_members, division FROM teams ORDER BY created_at ASC, team_name ASC
 `
 
-func (q *Queries) ListTeamMembers(ctx context.Context, teamName string) ([]TeamMember, error) {
-	rows, err := q.query(ctx, q.listTeamMembersStmt, listTeamMembers, teamName)
+func (q *Queries) ListAllTeams(ctx context.Context) ([]Team, error) {
+	rows, err := q.query(ctx, q.listAllTeamsStmt, listAllTeams)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TeamMember
+	var items []Team
 	for rows.Next() {
-		var i TeamMember
+		var i Team
 		if err := rows.Scan(
 			&i.TeamName,
-			&i.Username,
-			&i.JoinedAt,
-			&i.IsLeader,
+			&i.CreatedAt,
+			&i.InviteCode,
+			&i.AcceptingMembers,
+			&i.Division,
 		); err != nil {
 			return nil, err
 		}
@@ -431,26 +822,28 @@ func (q *Queries) ListTeamMembers(ctx context.Context, teamName string) ([]TeamM
 	return items, nil
 }
 
-const listTeams = `-- name: ListTeams :many
-SELECT team_name, created_at, accepting_members FROM teams
+const listAuditLog = `-- name: ListAuditLog :many
+SELECT id, created_at, actor, action, arguments, before, after FROM audit_log ORDER BY id ASC
 `
 
-type ListTeamsRow struct {
-	TeamName         string
-	CreatedAt        DateTime
-	AcceptingMembers bool
-}
-
-func (q *Queries) ListTeams(ctx context.Context) ([]ListTeamsRow, error) {
-	rows, err := q.query(ctx, q.listTeamsStmt, listTeams)
+func (q *Queries) ListAuditLog(ctx context.Context) ([]AuditLog, error) {
+	rows, err := q.query(ctx, q.listAuditLogStmt, listAuditLog)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListTeamsRow
+	var items []AuditLog
 	for rows.Next() {
-		var i ListTeamsRow
-		if err := rows.Scan(&i.TeamName, &i.CreatedAt, &i.AcceptingMembers); err != nil {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Actor,
+			&i.Action,
+			&i.Arguments,
+			&i.Before,
+			&i.After,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -464,58 +857,539 @@ func (q *Queries) ListTeams(ctx context.Context) ([]ListTeamsRow, error) {
 	return items, nil
 }
 
-const recordSubmission = `-- name: RecordSubmission :one
-INSERT INTO team_submit_attempts (team_name, submitted_by, problem_id, correct) VALUES (?, ?, ?, ?) RETURNING team_name, problem_id, submitted_at, correct, submitted_by
-`
-
-type RecordSubmissionParams struct {
-	TeamName    string
-	SubmittedBy sql.NullString
-	ProblemID   string
-	Correct     bool
-}
-
-func (q *Queries) RecordSubmission(ctx context.Context, arg RecordSubmissionParams) (TeamSubmitAttempt, error) {
-	row := q.queryRow(ctx, q.recordSubmissionStmt, recordSubmission,
-		arg.TeamName,
-		arg.SubmittedBy,
-		arg.ProblemID,
-		arg.Correct,
-	)
-	var i TeamSubmitAttempt
-	err := row.Scan(
-		&i.TeamName,
-		&i.ProblemID,
-		&i.SubmittedAt,
-		&i.Correct,
-		&i.SubmittedBy,
-	)
-	return i, err
-}
-
-const removePointsByReason = `-- name: RemovePointsByReason :many
-DELETE FROM team_points WHERE team_name = ? AND reason = ? RETURNING team_name, added_at, points, reason
+const listFirstSolves = `-- name: ListFirstSolves :many
+SELECT division, problem_id, rank, team_name, solved_at, bonus FROM first_solves ORDER BY solved_at ASC, problem_id ASC, rank ASC
 `
 
-type RemovePointsByReasonParams struct {
-	TeamName string
-	Reason   string
-}
-
-func (q *Queries) RemovePointsByReason(ctx context.Context, arg RemovePointsByReasonParams) ([]TeamPoint, error) {
-	rows, err := q.query(ctx, q.removePointsByReasonStmt, removePointsByReason, arg.TeamName, arg.Reason)
+func (q *Queries) ListFirstSolves(ctx context.Context) ([]FirstSolve, error) {
+	rows, err := q.query(ctx, q.listFirstSolvesStmt, listFirstSolves)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TeamPoint
+	var items []FirstSolve
 	for rows.Next() {
-		var i TeamPoint
+		var i FirstSolve
 		if err := rows.Scan(
+			&i.Division,
+			&i.ProblemID,
+			&i.Rank,
+			&i.TeamName,
+			&i.SolvedAt,
+			&i.Bonus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeaderboardReveals = `-- name: ListLeaderboardReveals :many
+SELECT division, revealed, updated_at FROM leaderboard_reveals ORDER BY division ASC
+`
+
+func (q *Queries) ListLeaderboardReveals(ctx context.Context) ([]LeaderboardReveal, error) {
+	rows, err := q.query(ctx, q.listLeaderboardRevealsStmt, listLeaderboardReveals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LeaderboardReveal
+	for rows.Next() {
+		var i LeaderboardReveal
+		if err := rows.Scan(&i.Division, &i.Revealed, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPoints = `-- name: ListPoints :many
+SELECT id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor FROM team_points ORDER BY id ASC
+`
+
+func (q *Queries) ListPoints(ctx context.Context) ([]TeamPoint, error) {
+	rows, err := q.query(ctx, q.listPointsStmt, listPoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamPoint
+	for rows.Next() {
+		var i TeamPoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamName,
+			&i.AddedAt,
+			&i.Points,
+			&i.Reason,
+			&i.ProblemID,
+			&i.Source,
+			&i.Part,
+			&i.SubmissionID,
+			&i.Actor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProblemStarts = `-- name: ListProblemStarts :many
+SELECT team_name, problem_id, started_at, started_by FROM problem_starts ORDER BY started_at ASC, team_name ASC, problem_id ASC
+`
+
+func (q *Queries) ListProblemStarts(ctx context.Context) ([]ProblemStart, error) {
+	rows, err := q.query(ctx, q.listProblemStartsStmt, listProblemStarts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemStart
+	for rows.Next() {
+		var i ProblemStart
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.ProblemID,
+			&i.StartedAt,
+			&i.StartedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduleOverrides = `-- name: ListScheduleOverrides :many
+SELECT id, created_at, action, problem_index, delay_seconds, reason, division FROM schedule_overrides ORDER BY id ASC
+`
+
+func (q *Queries) ListScheduleOverrides(ctx context.Context) ([]ScheduleOverride, error) {
+	rows, err := q.query(ctx, q.listScheduleOverridesStmt, listScheduleOverrides)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduleOverride
+	for rows.Next() {
+		var i ScheduleOverride
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Action,
+			&i.ProblemIndex,
+			&i.DelaySeconds,
+			&i.Reason,
+			&i.Division,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissions = `-- name: ListSubmissions :many
+SELECT id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer FROM team_submit_attempts WHERE team_name = ? AND problem_id = ?
+	ORDER BY submitted_at ASC
+`
+
+type ListSubmissionsParams struct {
+	TeamName  string
+	ProblemID string
+}
+
+func (q *Queries) ListSubmissions(ctx context.Context, arg ListSubmissionsParams) ([]TeamSubmitAttempt, error) {
+	rows, err := q.query(ctx, q.listSubmissionsStmt, listSubmissions, arg.TeamName, arg.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamSubmitAttempt
+	for rows.Next() {
+		var i TeamSubmitAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamName,
+			&i.ProblemID,
+			&i.SubmittedAt,
+			&i.Correct,
+			&i.SubmittedBy,
+			&i.Practice,
+			&i.Answer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmitAttempts = `-- name: ListSubmitAttempts :many
+SELECT id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer FROM team_submit_attempts ORDER BY id ASC
+`
+
+func (q *Queries) ListSubmitAttempts(ctx context.Context) ([]TeamSubmitAttempt, error) {
+	rows, err := q.query(ctx, q.listSubmitAttemptsStmt, listSubmitAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamSubmitAttempt
+	for rows.Next() {
+		var i TeamSubmitAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamName,
+			&i.ProblemID,
+			&i.SubmittedAt,
+			&i.Correct,
+			&i.SubmittedBy,
+			&i.Practice,
+			&i.Answer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmittedProblemIDs = `-- name: ListSubmittedProblemIDs :many
+SELECT DISTINCT problem_id FROM team_submit_attempts
+`
+
+func (q *Queries) ListSubmittedProblemIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.query(ctx, q.listSubmittedProblemIDsStmt, listSubmittedProblemIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var problem_id string
+		if err := rows.Scan(&problem_id); err != nil {
+			return nil, err
+		}
+		items = append(items, problem_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamAndMembers = `-- name: ListTeamAndMembers :many
+SELECT team_name, user_name FROM team_members ORDER BY joined_at ASC
+`
+
+type ListTeamAndMembersRow struct {
+	TeamName string
+	Username string
+}
+
+func (q *Queries) ListTeamAndMembers(ctx context.Context) ([]ListTeamAndMembersRow, error) {
+	rows, err := q.query(ctx, q.listTeamAndMembersStmt, listTeamAndMembers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamAndMembersRow
+	for rows.Next() {
+		var i ListTeamAndMembersRow
+		if err := rows.Scan(&i.TeamName, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamMembers = `-- name: ListTeamMembers :many
+SELECT team_name, user_name, joined_at, is_leader FROM team_members WHERE team_name = ? ORDER BY joined_at ASC
+`
+
+func (q *Queries) ListTeamMembers(ctx context.Context, teamName string) ([]TeamMember, error) {
+	rows, err := q.query(ctx, q.listTeamMembersStmt, listTeamMembers, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamMember
+	for rows.Next() {
+		var i TeamMember
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.Username,
+			&i.JoinedAt,
+			&i.IsLeader,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeams = `-- name: ListTeams :many
+SELECT team_name, created_at, accepting_members, division FROM teams
+`
+
+type ListTeamsRow struct {
+	TeamName         string
+	CreatedAt        DateTime
+	AcceptingMembers bool
+	Division         string
+}
+
+func (q *Queries) ListTeams(ctx context.Context) ([]ListTeamsRow, error) {
+	rows, err := q.query(ctx, q.listTeamsStmt, listTeams)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamsRow
+	for rows.Next() {
+		var i ListTeamsRow
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.CreatedAt,
+			&i.AcceptingMembers,
+			&i.Division,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVoidedProblems = `-- name: ListVoidedProblems :many
+SELECT division, problem_id, voided_at, credit, reason FROM voided_problems ORDER BY voided_at ASC
+`
+
+func (q *Queries) ListVoidedProblems(ctx context.Context) ([]VoidedProblem, error) {
+	rows, err := q.query(ctx, q.listVoidedProblemsStmt, listVoidedProblems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VoidedProblem
+	for rows.Next() {
+		var i VoidedProblem
+		if err := rows.Scan(
+			&i.Division,
+			&i.ProblemID,
+			&i.VoidedAt,
+			&i.Credit,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const problemPointsByTeam = `-- name: ProblemPointsByTeam :many
+SELECT team_points.team_name, team_points.source, SUM(team_points.points) AS points
+	FROM team_points
+	JOIN teams ON teams.team_name = team_points.team_name
+	WHERE team_points.problem_id = ? AND teams.division = ?
+	GROUP BY team_points.team_name, team_points.source
+`
+
+type ProblemPointsByTeamParams struct {
+	ProblemID sql.NullString
+	Division  string
+}
+
+type ProblemPointsByTeamRow struct {
+	TeamName string
+	Source   string
+	Points   sql.NullFloat64
+}
+
+func (q *Queries) ProblemPointsByTeam(ctx context.Context, arg ProblemPointsByTeamParams) ([]ProblemPointsByTeamRow, error) {
+	rows, err := q.query(ctx, q.problemPointsByTeamStmt, problemPointsByTeam, arg.ProblemID, arg.Division)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemPointsByTeamRow
+	for rows.Next() {
+		var i ProblemPointsByTeamRow
+		if err := rows.Scan(&i.TeamName, &i.Source, &i.Points); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const problemStartTime = `-- name: ProblemStartTime :one
+SELECT started_at FROM problem_starts WHERE team_name = ? AND problem_id = ?
+`
+
+type ProblemStartTimeParams struct {
+	TeamName  string
+	ProblemID string
+}
+
+func (q *Queries) ProblemStartTime(ctx context.Context, arg ProblemStartTimeParams) (DateTime, error) {
+	row := q.queryRow(ctx, q.problemStartTimeStmt, problemStartTime, arg.TeamName, arg.ProblemID)
+	var started_at DateTime
+	err := row.Scan(&started_at)
+	return started_at, err
+}
+
+const recordSubmission = `-- name: RecordSubmission :one
+INSERT INTO team_submit_attempts (team_name, submitted_by, problem_id, correct, practice, submitted_at, answer) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id, team_name, problem_id, submitted_at, correct, submitted_by, practice, answer
+`
+
+type RecordSubmissionParams struct {
+	TeamName    string
+	SubmittedBy sql.NullString
+	ProblemID   string
+	Correct     bool
+	Practice    bool
+	SubmittedAt DateTime
+	Answer      sql.NullInt64
+}
+
+func (q *Queries) RecordSubmission(ctx context.Context, arg RecordSubmissionParams) (TeamSubmitAttempt, error) {
+	row := q.queryRow(ctx, q.recordSubmissionStmt, recordSubmission,
+		arg.TeamName,
+		arg.SubmittedBy,
+		arg.ProblemID,
+		arg.Correct,
+		arg.Practice,
+		arg.SubmittedAt,
+		arg.Answer,
+	)
+	var i TeamSubmitAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.TeamName,
+		&i.ProblemID,
+		&i.SubmittedAt,
+		&i.Correct,
+		&i.SubmittedBy,
+		&i.Practice,
+		&i.Answer,
+	)
+	return i, err
+}
+
+const removePointsByReason = `-- name: RemovePointsByReason :many
+DELETE FROM team_points WHERE team_name = ? AND source = ? AND reason = ? RETURNING id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor
+`
+
+type RemovePointsByReasonParams struct {
+	TeamName string
+	Source   string
+	Reason   string
+}
+
+func (q *Queries) RemovePointsByReason(ctx context.Context, arg RemovePointsByReasonParams) ([]TeamPoint, error) {
+	rows, err := q.query(ctx, q.removePointsByReasonStmt, removePointsByReason, arg.TeamName, arg.Source, arg.Reason)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamPoint
+	for rows.Next() {
+		var i TeamPoint
+		if err := rows.Scan(
+			&i.ID,
 			&i.TeamName,
 			&i.AddedAt,
 			&i.Points,
 			&i.Reason,
+			&i.ProblemID,
+			&i.Source,
+			&i.Part,
+			&i.SubmissionID,
+			&i.Actor,
 		); err != nil {
 			return nil, err
 		}
@@ -531,7 +1405,7 @@ func (q *Queries) RemovePointsByReason(ctx context.Context, arg RemovePointsByRe
 }
 
 const removePointsByTime = `-- name: RemovePointsByTime :one
-DELETE FROM team_points WHERE team_name = ? AND added_at = ? RETURNING team_name, added_at, points, reason
+DELETE FROM team_points WHERE team_name = ? AND added_at = ? RETURNING id, team_name, added_at, points, reason, problem_id, source, part, submission_id, actor
 `
 
 type RemovePointsByTimeParams struct {
@@ -543,16 +1417,122 @@ func (q *Queries) RemovePointsByTime(ctx context.Context, arg RemovePointsByTime
 	row := q.queryRow(ctx, q.removePointsByTimeStmt, removePointsByTime, arg.TeamName, arg.AddedAt)
 	var i TeamPoint
 	err := row.Scan(
+		&i.ID,
 		&i.TeamName,
 		&i.AddedAt,
 		&i.Points,
 		&i.Reason,
+		&i.ProblemID,
+		&i.Source,
+		&i.Part,
+		&i.SubmissionID,
+		&i.Actor,
 	)
 	return i, err
 }
 
+const renameFirstSolvesProblemID = `-- name: RenameFirstSolvesProblemID :execrows
+UPDATE first_solves SET problem_id = ? WHERE problem_id = ?
+`
+
+type RenameFirstSolvesProblemIDParams struct {
+	NewProblemID string
+	OldProblemID string
+}
+
+func (q *Queries) RenameFirstSolvesProblemID(ctx context.Context, arg RenameFirstSolvesProblemIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.renameFirstSolvesProblemIDStmt, renameFirstSolvesProblemID, arg.NewProblemID, arg.OldProblemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renamePointsProblemID = `-- name: RenamePointsProblemID :execrows
+UPDATE team_points SET problem_id = ? WHERE problem_id = ?
+`
+
+type RenamePointsProblemIDParams struct {
+	NewProblemID sql.NullString
+	OldProblemID sql.NullString
+}
+
+func (q *Queries) RenamePointsProblemID(ctx context.Context, arg RenamePointsProblemIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.renamePointsProblemIDStmt, renamePointsProblemID, arg.NewProblemID, arg.OldProblemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameProblemStartsProblemID = `-- name: RenameProblemStartsProblemID :execrows
+UPDATE problem_starts SET problem_id = ? WHERE problem_id = ?
+`
+
+type RenameProblemStartsProblemIDParams struct {
+	NewProblemID string
+	OldProblemID string
+}
+
+func (q *Queries) RenameProblemStartsProblemID(ctx context.Context, arg RenameProblemStartsProblemIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.renameProblemStartsProblemIDStmt, renameProblemStartsProblemID, arg.NewProblemID, arg.OldProblemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameSubmissionsProblemID = `-- name: RenameSubmissionsProblemID :execrows
+UPDATE team_submit_attempts SET problem_id = ? WHERE problem_id = ?
+`
+
+type RenameSubmissionsProblemIDParams struct {
+	NewProblemID string
+	OldProblemID string
+}
+
+func (q *Queries) RenameSubmissionsProblemID(ctx context.Context, arg RenameSubmissionsProblemIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.renameSubmissionsProblemIDStmt, renameSubmissionsProblemID, arg.NewProblemID, arg.OldProblemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameVoidedProblemID = `-- name: RenameVoidedProblemID :execrows
+UPDATE voided_problems SET problem_id = ? WHERE problem_id = ?
+`
+
+type RenameVoidedProblemIDParams struct {
+	NewProblemID string
+	OldProblemID string
+}
+
+func (q *Queries) RenameVoidedProblemID(ctx context.Context, arg RenameVoidedProblemIDParams) (int64, error) {
+	result, err := q.exec(ctx, q.renameVoidedProblemIDStmt, renameVoidedProblemID, arg.NewProblemID, arg.OldProblemID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resetLeaderboardReveal = `-- name: ResetLeaderboardReveal :exec
+DELETE FROM leaderboard_reveals WHERE division = ?
+`
+
+func (q *Queries) ResetLeaderboardReveal(ctx context.Context, division string) error {
+	_, err := q.exec(ctx, q.resetLeaderboardRevealStmt, resetLeaderboardReveal, division)
+	return err
+}
+
 const setHackathonSubmission = `-- name: SetHackathonSubmission :exec
-REPLACE INTO hackathon_submissions (team_name, project_url, project_description, category) VALUES (?, ?, ?, ?)
+INSERT INTO hackathon_submissions (team_name, project_url, project_description, category, submitted_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (team_name) DO UPDATE SET
+		submitted_at = excluded.submitted_at,
+		project_url = excluded.project_url,
+		project_description = excluded.project_description,
+		category = excluded.category,
+		won_rank = NULL
 `
 
 type SetHackathonSubmissionParams struct {
@@ -560,6 +1540,7 @@ type SetHackathonSubmissionParams struct {
 	ProjectUrl         string
 	ProjectDescription sql.NullString
 	Category           string
+	SubmittedAt        DateTime
 }
 
 func (q *Queries) SetHackathonSubmission(ctx context.Context, arg SetHackathonSubmissionParams) error {
@@ -568,6 +1549,7 @@ func (q *Queries) SetHackathonSubmission(ctx context.Context, arg SetHackathonSu
 		arg.ProjectUrl,
 		arg.ProjectDescription,
 		arg.Category,
+		arg.SubmittedAt,
 	)
 	return err
 }
@@ -586,6 +1568,58 @@ func (q *Queries) SetHackathonWinner(ctx context.Context, arg SetHackathonWinner
 	return err
 }
 
+const setLeaderboardReveal = `-- name: SetLeaderboardReveal :one
+INSERT INTO leaderboard_reveals (division, revealed, updated_at) VALUES (?, ?, ?)
+	ON CONFLICT (division) DO UPDATE SET revealed = excluded.revealed, updated_at = excluded.updated_at
+	RETURNING division, revealed, updated_at
+`
+
+type SetLeaderboardRevealParams struct {
+	Division  string
+	Revealed  int64
+	UpdatedAt DateTime
+}
+
+func (q *Queries) SetLeaderboardReveal(ctx context.Context, arg SetLeaderboardRevealParams) (LeaderboardReveal, error) {
+	row := q.queryRow(ctx, q.setLeaderboardRevealStmt, setLeaderboardReveal, arg.Division, arg.Revealed, arg.UpdatedAt)
+	var i LeaderboardReveal
+	err := row.Scan(&i.Division, &i.Revealed, &i.UpdatedAt)
+	return i, err
+}
+
+const startProblem = `-- name: StartProblem :exec
+INSERT INTO problem_starts (team_name, problem_id, started_at, started_by) VALUES (?, ?, ?, ?)
+	ON CONFLICT DO NOTHING
+`
+
+type StartProblemParams struct {
+	TeamName  string
+	ProblemID string
+	StartedAt DateTime
+	StartedBy string
+}
+
+func (q *Queries) StartProblem(ctx context.Context, arg StartProblemParams) error {
+	_, err := q.exec(ctx, q.startProblemStmt, startProblem,
+		arg.TeamName,
+		arg.ProblemID,
+		arg.StartedAt,
+		arg.StartedBy,
+	)
+	return err
+}
+
+const teamDivision = `-- name: TeamDivision :one
+SELECT division FROM teams WHERE team_name = ?
+`
+
+func (q *Queries) TeamDivision(ctx context.Context, teamName string) (string, error) {
+	row := q.queryRow(ctx, q.teamDivisionStmt, teamDivision, teamName)
+	var division string
+	err := row.Scan(&division)
+	return division, err
+}
+
 const teamInviteCode = `-- name: TeamInviteCode :one
 SELECT invite_code FROM teams WHERE team_name = ?
 `
@@ -597,41 +1631,6 @@ func (q *Queries) TeamInviteCode(ctx context.Context, teamName string) (string,
 	return invite_code, err
 }
 
-const teamPointsEach = `-- name: TeamPointsEach :many
-SELECT team_name, reason, SUM(points) AS points
-	FROM team_points
-	GROUP BY team_name, reason
-`
-
-type TeamPointsEachRow struct {
-	TeamName string
-	Reason   string
-	Points   sql.NullFloat64
-}
-
-func (q *Queries) TeamPointsEach(ctx context.Context) ([]TeamPointsEachRow, error) {
-	rows, err := q.query(ctx, q.teamPointsEachStmt, teamPointsEach)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TeamPointsEachRow
-	for rows.Next() {
-		var i TeamPointsEachRow
-		if err := rows.Scan(&i.TeamName, &i.Reason, &i.Points); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const teamPointsHistory = `-- name: TeamPointsHistory :many
 SELECT team_name, added_at, points
 	FROM (
@@ -673,37 +1672,33 @@ func (q *Queries) TeamPointsHistory(ctx context.Context) ([]TeamPointsHistoryRow
 	return items, nil
 }
 
-const teamPointsTotal = `-- name: TeamPointsTotal :many
-SELECT team_name, SUM(points) AS points
-	FROM team_points
-	GROUP BY team_name
-	ORDER BY COALESCE(SUM(points), 0) DESC
+const voidProblem = `-- name: VoidProblem :one
+INSERT INTO voided_problems (division, problem_id, credit, reason, voided_at) VALUES (?, ?, ?, ?, ?) RETURNING division, problem_id, voided_at, credit, reason
 `
 
-type TeamPointsTotalRow struct {
-	TeamName string
-	Points   sql.NullFloat64
+type VoidProblemParams struct {
+	Division  string
+	ProblemID string
+	Credit    float64
+	Reason    string
+	VoidedAt  DateTime
 }
 
-func (q *Queries) TeamPointsTotal(ctx context.Context) ([]TeamPointsTotalRow, error) {
-	rows, err := q.query(ctx, q.teamPointsTotalStmt, teamPointsTotal)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TeamPointsTotalRow
-	for rows.Next() {
-		var i TeamPointsTotalRow
-		if err := rows.Scan(&i.TeamName, &i.Points); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) VoidProblem(ctx context.Context, arg VoidProblemParams) (VoidedProblem, error) {
+	row := q.queryRow(ctx, q.voidProblemStmt, voidProblem,
+		arg.Division,
+		arg.ProblemID,
+		arg.Credit,
+		arg.Reason,
+		arg.VoidedAt,
+	)
+	var i VoidedProblem
+	err := row.Scan(
+		&i.Division,
+		&i.ProblemID,
+		&i.VoidedAt,
+		&i.Credit,
+		&i.Reason,
+	)
+	return i, err
 }
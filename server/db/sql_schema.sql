@@ -65,3 +65,209 @@ CREATE TABLE hackathon_submissions (
 	category TEXT NOT NULL,
 	won_rank INTEGER DEFAULT NULL UNIQUE CHECK (won_rank IS NULL OR (won_rank > 0 AND won_rank <= 3)),
 	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Track runtime overrides to the problem release schedule. Rows are never
+-- deleted, so the table also serves as an audit log of every override.
+CREATE TABLE schedule_overrides (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	action TEXT NOT NULL CHECK (action IN ('delay', 'pause', 'resume', 'release')),
+	problem_index INTEGER,
+	delay_seconds INTEGER,
+	reason TEXT NOT NULL DEFAULT '');
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Submissions made after the competition ends are practice attempts. They are
+-- still checked but award no points.
+ALTER TABLE team_submit_attempts ADD COLUMN
+	practice BOOLEAN NOT NULL DEFAULT FALSE;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Teams compete in a division, each with its own problems and leaderboard.
+-- The empty division is used when the competition has no divisions.
+ALTER TABLE teams ADD COLUMN
+	division TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE schedule_overrides ADD COLUMN
+	division TEXT NOT NULL DEFAULT '';
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Give each point entry its own ID and track which problem part it was awarded
+-- for, so that points can be traced back and voided per problem. The old
+-- primary key (team_name, added_at) also collided when a team earned points
+-- twice within the same second.
+CREATE TABLE team_points_new (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	team_name TEXT NOT NULL,
+	added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	points REAL NOT NULL,
+	reason TEXT NOT NULL,
+	problem_id TEXT,
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+-- Week of code points were awarded right after the correct submission, so the
+-- problem is recovered from the team's latest correct submission at or before
+-- the points. This is a best effort: if a team solved both parts within the
+-- same second, both entries get the problem of the later submission. Entries
+-- that match no submission, such as admin awards, keep a NULL problem and are
+-- not affected by voiding.
+INSERT INTO team_points_new (team_name, added_at, points, reason, problem_id)
+	SELECT team_name, added_at, points, reason, (
+		SELECT problem_id FROM team_submit_attempts
+			WHERE team_points.reason = 'week of code'
+			AND team_submit_attempts.team_name = team_points.team_name
+			AND team_submit_attempts.submitted_at <= team_points.added_at
+			AND team_submit_attempts.correct = TRUE
+			ORDER BY team_submit_attempts.submitted_at DESC
+			LIMIT 1
+	)
+	FROM team_points
+	ORDER BY added_at ASC;
+
+DROP TABLE team_points;
+
+ALTER TABLE team_points_new RENAME TO team_points;
+
+CREATE INDEX team_points_team_name_idx ON team_points (team_name);
+CREATE INDEX team_points_problem_id_idx ON team_points (problem_id);
+
+-- Problem parts that were voided, e.g. because their input generator was
+-- broken. Voided parts award no points. Points previously earned from them are
+-- canceled out by negative entries in team_points, and every team may be
+-- credited a uniform amount instead.
+CREATE TABLE voided_problems (
+	division TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	voided_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	credit REAL NOT NULL DEFAULT 0,
+	reason TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (division, problem_id));
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- The first teams to solve each problem part, who are awarded a first solve
+-- bonus. The primary key guarantees that no two teams get the same rank, even
+-- if they submit at the same time.
+CREATE TABLE first_solves (
+	division TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	rank INTEGER NOT NULL CHECK (rank > 0),
+	team_name TEXT NOT NULL,
+	solved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	bonus REAL NOT NULL,
+	PRIMARY KEY (division, problem_id, rank),
+	UNIQUE (division, problem_id, team_name),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- The first time that a member of each team opened a problem or fetched its
+-- input. Problems may measure the score decay of a team from this time instead
+-- of from the problem's release.
+CREATE TABLE problem_starts (
+	team_name TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	started_by TEXT NOT NULL,
+	PRIMARY KEY (team_name, problem_id),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Give each submission its own ID, so that point entries can reference the
+-- submission that produced them.
+CREATE TABLE team_submit_attempts_new (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	team_name TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	submitted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	correct BOOLEAN NOT NULL,
+	submitted_by TEXT REFERENCES team_members (user_name),
+	practice BOOLEAN NOT NULL DEFAULT FALSE,
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+INSERT INTO team_submit_attempts_new (team_name, problem_id, submitted_at, correct, submitted_by, practice)
+	SELECT team_name, problem_id, submitted_at, correct, submitted_by, practice
+	FROM team_submit_attempts
+	ORDER BY submitted_at ASC, rowid ASC;
+
+DROP TABLE team_submit_attempts;
+
+ALTER TABLE team_submit_attempts_new RENAME TO team_submit_attempts;
+
+CREATE INDEX team_submit_attempts_team_name_idx ON team_submit_attempts (team_name, problem_id);
+
+-- Turn team_points into a ledger whose entries record where their points came
+-- from: the type of source, the problem part, the submission that produced
+-- them and the admin who made them, if any. The reason is kept as a free-form
+-- description.
+ALTER TABLE team_points ADD COLUMN
+	source TEXT NOT NULL DEFAULT 'admin';
+
+ALTER TABLE team_points ADD COLUMN
+	part INTEGER;
+
+ALTER TABLE team_points ADD COLUMN
+	submission_id INTEGER REFERENCES team_submit_attempts (id);
+
+ALTER TABLE team_points ADD COLUMN
+	actor TEXT;
+
+UPDATE team_points SET source = CASE reason
+	WHEN 'week of code' THEN 'solve'
+	WHEN 'wrong answer penalty' THEN 'penalty'
+	WHEN 'first solve bonus' THEN 'first_solve'
+	WHEN 'voided problem' THEN 'void'
+	WHEN 'hackathon' THEN 'hackathon'
+	ELSE 'admin'
+END;
+
+UPDATE team_points SET part = CAST(substr(problem_id, -1) AS INTEGER)
+	WHERE problem_id LIKE '%/part1' OR problem_id LIKE '%/part2';
+
+UPDATE team_points SET submission_id = (
+	SELECT id FROM team_submit_attempts
+		WHERE team_submit_attempts.team_name = team_points.team_name
+		AND team_submit_attempts.problem_id = team_points.problem_id
+		AND team_submit_attempts.correct = TRUE
+		AND team_submit_attempts.practice = FALSE
+		LIMIT 1
+)
+	WHERE source IN ('solve', 'penalty', 'first_solve');
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- The progress of the closing ceremony reveal of each division's frozen
+-- leaderboard. The reveal has started if a division has a row, and revealed is
+-- the number of teams revealed so far, from the bottom up.
+CREATE TABLE leaderboard_reveals (
+	division TEXT PRIMARY KEY,
+	revealed INTEGER NOT NULL DEFAULT 0 CHECK (revealed >= 0),
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- The audit log records every change made by an admin, through competitionctl
+-- or the admin endpoints. The arguments and the before and after values are
+-- JSON; before and after are NULL when there's nothing to record.
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	actor TEXT NOT NULL,
+	action TEXT NOT NULL,
+	arguments TEXT NOT NULL DEFAULT '{}',
+	before TEXT,
+	after TEXT);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Record the answer of every submission, so that disputes can be reviewed and
+-- identical wrong answers can be rejected. Older submissions have no answer.
+ALTER TABLE team_submit_attempts ADD COLUMN answer INTEGER;
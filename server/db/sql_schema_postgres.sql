@@ -0,0 +1,121 @@
+-- The PostgreSQL schema mirrors sql_schema.sql, which remains the source of
+-- truth for sqlc. Both schemas must keep the same columns in the same order,
+-- since the queries select them with *. Every new version of sql_schema.sql
+-- needs a matching new version here.
+--
+-- Timestamps are stored in UTC without a time zone, like SQLite's
+-- CURRENT_TIMESTAMP.
+
+CREATE TABLE teams (
+	team_name TEXT PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	invite_code TEXT NOT NULL,
+	accepting_members BOOLEAN NOT NULL DEFAULT TRUE,
+	division TEXT NOT NULL DEFAULT '');
+
+CREATE UNIQUE INDEX teams_invite_code_idx ON teams (invite_code);
+
+CREATE TABLE team_members (
+	team_name TEXT NOT NULL,
+	user_name TEXT NOT NULL,
+	joined_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	is_leader BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (team_name, user_name),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE UNIQUE INDEX team_members_user_name_idx ON team_members (user_name);
+
+CREATE TABLE team_submit_attempts (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	team_name TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	submitted_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	correct BOOLEAN NOT NULL,
+	submitted_by TEXT REFERENCES team_members (user_name),
+	practice BOOLEAN NOT NULL DEFAULT FALSE,
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE INDEX team_submit_attempts_team_name_idx ON team_submit_attempts (team_name, problem_id);
+
+CREATE TABLE team_points (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	team_name TEXT NOT NULL,
+	added_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	points DOUBLE PRECISION NOT NULL,
+	reason TEXT NOT NULL,
+	problem_id TEXT,
+	source TEXT NOT NULL DEFAULT 'admin',
+	part INTEGER,
+	submission_id BIGINT REFERENCES team_submit_attempts (id),
+	actor TEXT,
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE INDEX team_points_team_name_idx ON team_points (team_name);
+CREATE INDEX team_points_problem_id_idx ON team_points (problem_id);
+
+CREATE TABLE hackathon_submissions (
+	team_name TEXT NOT NULL PRIMARY KEY,
+	submitted_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	project_url TEXT NOT NULL,
+	project_description TEXT,
+	category TEXT NOT NULL,
+	won_rank INTEGER DEFAULT NULL UNIQUE CHECK (won_rank IS NULL OR (won_rank > 0 AND won_rank <= 3)),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE TABLE schedule_overrides (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	action TEXT NOT NULL CHECK (action IN ('delay', 'pause', 'resume', 'release')),
+	problem_index INTEGER,
+	delay_seconds INTEGER,
+	reason TEXT NOT NULL DEFAULT '',
+	division TEXT NOT NULL DEFAULT '');
+
+CREATE TABLE voided_problems (
+	division TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	voided_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	credit DOUBLE PRECISION NOT NULL DEFAULT 0,
+	reason TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (division, problem_id));
+
+CREATE TABLE first_solves (
+	division TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	rank INTEGER NOT NULL CHECK (rank > 0),
+	team_name TEXT NOT NULL,
+	solved_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	bonus DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (division, problem_id, rank),
+	UNIQUE (division, problem_id, team_name),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE TABLE problem_starts (
+	team_name TEXT NOT NULL,
+	problem_id TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	started_by TEXT NOT NULL,
+	PRIMARY KEY (team_name, problem_id),
+	FOREIGN KEY (team_name) REFERENCES teams (team_name));
+
+CREATE TABLE leaderboard_reveals (
+	division TEXT PRIMARY KEY,
+	revealed INTEGER NOT NULL DEFAULT 0 CHECK (revealed >= 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'));
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE audit_log (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+	actor TEXT NOT NULL,
+	action TEXT NOT NULL,
+	arguments TEXT NOT NULL DEFAULT '{}',
+	before TEXT,
+	after TEXT);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+ALTER TABLE team_submit_attempts ADD COLUMN answer BIGINT;
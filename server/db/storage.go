@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "embed"
 
@@ -23,22 +24,50 @@ PRAGMA journal_mode = WAL;
 PRAGMA strict = ON;
 `
 
+// Dialect is the SQL dialect of a database.
+type Dialect string
+
+const (
+	SQLite     Dialect = "sqlite"
+	PostgreSQL Dialect = "postgres"
+)
+
 // Database provides methods for interacting with the database.
 // For now, it just wraps around sqlc's Queries because I'm lazy.
 type Database struct {
 	*Queries
-	db *sql.DB
+	db      *sql.DB
+	dialect Dialect
 }
 
-// Open creates a new database at the given path.
-func Open(path string) (*Database, error) {
-	db, err := sql.Open("sqlite", path)
+// Open opens the database at the given URL, migrating it if needed. URLs
+// starting with postgres:// or postgresql:// open a PostgreSQL database.
+// Anything else is the path of a SQLite database, which is created if it
+// doesn't exist yet.
+func Open(url string) (*Database, error) {
+	if isPostgresURL(url) {
+		return openPostgres(url)
+	}
+
+	db, err := sql.Open("sqlite", sqliteDSN(strings.TrimPrefix(url, "sqlite://")))
 	if err != nil {
 		return nil, err
 	}
 	return newDatabase(db)
 }
 
+// sqliteDSN adds the connection parameters to the path of a SQLite database.
+// Transactions often read before they write, and SQLite fails instead of
+// waiting when such a transaction can't upgrade to a write lock, so every
+// transaction takes the write lock when it begins and waits for it.
+func sqliteDSN(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_txlock=immediate&_pragma=busy_timeout(10000)"
+}
+
 // NewInMemory creates a new in-memory database.
 func NewInMemory() (*Database, error) {
 	db, _ := sql.Open("sqlite", ":memory:")
@@ -58,9 +87,15 @@ func newDatabase(db *sql.DB) (*Database, error) {
 	return &Database{
 		Queries: New(db),
 		db:      db,
+		dialect: SQLite,
 	}, nil
 }
 
+// Dialect returns the SQL dialect of the database.
+func (db *Database) Dialect() Dialect {
+	return db.dialect
+}
+
 // Close closes the database.
 func (db *Database) Close() error {
 	return db.db.Close()
@@ -74,7 +109,18 @@ func (db *Database) Tx(f func(*Queries) error) error {
 	}
 	defer tx.Rollback()
 
-	if err := f(New(tx)); err != nil {
+	q := New(tx)
+	if db.dialect == PostgreSQL {
+		// Callers rely on SQLite having a single writer, e.g. to rank first
+		// solves, so PostgreSQL transactions are serialized with a lock that
+		// holds across server replicas.
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", postgresLockID); err != nil {
+			return fmt.Errorf("failed to lock database: %w", err)
+		}
+		q = New(postgresDBTX{tx})
+	}
+
+	if err := f(q); err != nil {
 		return err
 	}
 
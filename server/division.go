@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// Division is a division (or track) of the competition. Each division has its
+// own problem set and leaderboard. Teams pick a division when they register.
+type Division struct {
+	// ID is the unique ID of the division. The ID is empty if the competition
+	// only has a single division.
+	ID string
+	// Name is the display name of the division.
+	Name string
+	// Problems is the problem set of the division.
+	Problems *problem.ProblemSet
+}
+
+// Path returns the URL path prefix of the division's routes. It is empty for
+// the unnamed division.
+func (d *Division) Path() string {
+	if d.ID == "" {
+		return ""
+	}
+	return "/divisions/" + d.ID
+}
+
+// problemID returns the ID of the given part of the problem released on the
+// given day. Problems are resolved whether they are released or not, since
+// schedule overrides can hold back problems that already have submissions.
+func (d *Division) problemID(day problemDay, part2 bool) string {
+	problems := d.Problems.AllProblems()
+	if day.index() < 0 || day.index() >= len(problems) {
+		return ""
+	}
+	if part2 {
+		return problems[day.index()].ID + "/part2"
+	} else {
+		return problems[day.index()].ID + "/part1"
+	}
+}
+
+func (d *Division) parseProblemID(id string) (day problemDay, part2 bool, ok bool) {
+	switch {
+	case strings.HasSuffix(id, "/part1"):
+		part2 = false
+		id = strings.TrimSuffix(id, "/part1")
+	case strings.HasSuffix(id, "/part2"):
+		part2 = true
+		id = strings.TrimSuffix(id, "/part2")
+	default:
+		return
+	}
+	for i, problem := range d.Problems.AllProblems() {
+		if problem.ID == id {
+			day = problemDay(i + 1)
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// Divisions returns the current divisions of the competition. The returned
+// slice must not be modified.
+func (s *Server) Divisions() []Division {
+	return s.live.Load().Divisions
+}
+
+// division returns the division with the given ID, or nil if there is none.
+func (s *Server) division(id string) *Division {
+	divisions := s.Divisions()
+	for i := range divisions {
+		if divisions[i].ID == id {
+			return &divisions[i]
+		}
+	}
+	return nil
+}
+
+// defaultDivision returns the division shown to users without a team.
+func (s *Server) defaultDivision() *Division {
+	return &s.Divisions()[0]
+}
+
+// requestDivision returns the division that the request is scoped to. It is
+// the division in the URL if there is one, otherwise the division of the
+// user's team, otherwise the default division.
+func (s *Server) requestDivision(r *http.Request) (*Division, error) {
+	if id := chi.URLParam(r, "division"); id != "" {
+		division := s.division(id)
+		if division == nil {
+			return nil, fmt.Errorf("division %q not found", id)
+		}
+		return division, nil
+	}
+
+	if division := s.teamDivision(r); division != nil {
+		return division, nil
+	}
+
+	return s.defaultDivision(), nil
+}
+
+// teamDivision returns the division of the user's team. It returns nil if the
+// user is not on a team.
+func (s *Server) teamDivision(r *http.Request) *Division {
+	u := getAuthentication(r)
+	if u.TeamName == "" {
+		return nil
+	}
+
+	id, err := s.database.TeamDivision(r.Context(), u.TeamName)
+	if err != nil {
+		s.logger.WarnContext(r.Context(),
+			"failed to get team division",
+			"team", u.TeamName,
+			"err", err)
+		return nil
+	}
+
+	return s.division(id)
+}
+
+// voidedProblems returns the voided problem parts of the division, keyed by
+// their problem ID.
+func (s *Server) voidedProblems(ctx context.Context, division *Division) (map[string]db.VoidedProblem, error) {
+	rows, err := s.database.ListVoidedProblems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voided problems: %w", err)
+	}
+
+	voided := make(map[string]db.VoidedProblem, len(rows))
+	for _, row := range rows {
+		if row.Division == division.ID {
+			voided[row.ProblemID] = row
+		}
+	}
+	return voided, nil
+}
+
+// CheckProblemIDs returns an error if any recorded submission references a
+// problem that is in none of the divisions. This usually means that a problem
+// ID was changed without migrating the database, which would orphan the
+// submissions. Every configured problem counts, whether it is released or not.
+func CheckProblemIDs(ctx context.Context, database *db.Database, divisions []Division) error {
+	known := make(map[string]bool)
+	for i := range divisions {
+		for _, problem := range divisions[i].Problems.AllProblems() {
+			known[problem.ID+"/part1"] = true
+			known[problem.ID+"/part2"] = true
+		}
+	}
+
+	submitted, err := database.ListSubmittedProblemIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list submitted problem IDs: %w", err)
+	}
+
+	var unknown []string
+	for _, id := range submitted {
+		if !known[id] {
+			unknown = append(unknown, strconv.Quote(id))
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf(
+			"submissions reference unknown problem IDs %s, use competitionctl migrate-problem-ids to rename them",
+			strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+)
+
+// leaderboardFreeze describes which points the viewer of a leaderboard may
+// see. While the leaderboard is frozen, points added after the freeze are
+// hidden, except for the viewer's own team and for teams that were already
+// revealed.
+type leaderboardFreeze struct {
+	// FreezeAt is when the leaderboard froze. It is zero if the leaderboard
+	// is not frozen.
+	FreezeAt time.Time
+	// Revealing is true once the reveal has started, and Revealed lists the
+	// revealed teams.
+	Revealing bool
+	Revealed  map[string]bool
+	// TotalTeams is the number of teams that can be revealed.
+	TotalTeams int
+	viewer     string
+}
+
+// IsFrozen returns true if any points are hidden.
+func (f leaderboardFreeze) IsFrozen() bool {
+	return !f.FreezeAt.IsZero() && len(f.Revealed) < f.TotalTeams
+}
+
+// isLive returns true if all points of the team are visible.
+func (f leaderboardFreeze) isLive(team string) bool {
+	return f.FreezeAt.IsZero() || f.Revealed[team] || team == f.viewer
+}
+
+// isVisible returns true if something that the team did at the given time is
+// visible.
+func (f leaderboardFreeze) isVisible(team string, t time.Time) bool {
+	return f.isLive(team) || t.Before(f.FreezeAt)
+}
+
+// leaderboardFreeze returns the freeze of the division's leaderboard as seen
+// by the given team, which may be empty.
+func (s *Server) leaderboardFreeze(ctx context.Context, division *Division, data standingsData, viewer string) (leaderboardFreeze, error) {
+	cfg := s.live.Load().Leaderboard
+	if !cfg.IsFrozen(s.clock.Now()) {
+		return leaderboardFreeze{}, nil
+	}
+
+	freeze := leaderboardFreeze{
+		FreezeAt: cfg.FreezeAt,
+		viewer:   viewer,
+	}
+
+	order := data.revealOrder(division.ID, cfg)
+	freeze.TotalTeams = len(order)
+
+	reveal, err := s.database.GetLeaderboardReveal(ctx, division.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return freeze, fmt.Errorf("failed to get leaderboard reveal: %w", err)
+	}
+	if err == nil {
+		freeze.Revealing = true
+		freeze.Revealed = make(map[string]bool, reveal.Revealed)
+		for _, team := range order[:min(int(reveal.Revealed), len(order))] {
+			freeze.Revealed[team] = true
+		}
+	}
+
+	return freeze, nil
+}
+
+// RevealOrder returns the teams of the division in the order that they are
+// revealed in after the leaderboard froze. The next team to be revealed is
+// always the lowest ranked team that isn't revealed yet, as ranked by the
+// standings at the freeze.
+func RevealOrder(ctx context.Context, q *db.Queries, division string, cfg config.LeaderboardConfig) ([]string, error) {
+	data, err := loadStandingsData(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return data.revealOrder(division, cfg), nil
+}
+
+func (d standingsData) revealOrder(division string, cfg config.LeaderboardConfig) []string {
+	frozen := d.standings(division, func(_ string, t time.Time) bool {
+		return t.Before(cfg.FreezeAt)
+	})
+	RankStandings(frozen, cfg.TiebreakRules())
+
+	// Revealing a team never changes the ranks of the other unrevealed
+	// teams relative to each other, so the order is simply bottom up.
+	order := make([]string, 0, len(frozen))
+	for i := len(frozen) - 1; i >= 0; i-- {
+		if frozen[i].hasPoints {
+			order = append(order, frozen[i].TeamName)
+		}
+	}
+	return order
+}
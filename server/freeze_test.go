@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestLeaderboardFreeze(t *testing.T) {
+	freezeAt := time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC)
+	before := freezeAt.Add(-time.Hour)
+	after := freezeAt.Add(time.Hour)
+
+	entry := func(team string, points float64, t time.Time) db.TeamPoint {
+		return db.TeamPoint{TeamName: team, Points: points, AddedAt: db.NewDateTime(t)}
+	}
+
+	data := standingsData{
+		teams: []db.ListTeamsRow{
+			{TeamName: "a", Division: "div"},
+			{TeamName: "b", Division: "div"},
+			{TeamName: "c", Division: "div"},
+			{TeamName: "d", Division: "div"},
+			{TeamName: "other", Division: "other"},
+		},
+		points: []db.TeamPoint{
+			entry("a", 300, before),
+			entry("b", 200, before),
+			// c overtakes everyone after the freeze, which must not change
+			// its place in the reveal order.
+			entry("c", 100, before),
+			entry("c", 500, after),
+			entry("other", 1000, before),
+			// d only has points after the freeze, but is still revealed.
+			entry("d", 50, after),
+		},
+		submissions: []db.TeamSubmitAttempt{
+			{TeamName: "a", ProblemID: "p/part1", Correct: true, SubmittedAt: db.NewDateTime(before)},
+			{TeamName: "c", ProblemID: "p/part1", Correct: true, SubmittedAt: db.NewDateTime(after)},
+			{TeamName: "d", ProblemID: "p/part1", Correct: false, SubmittedAt: db.NewDateTime(after)},
+		},
+	}
+
+	cfg := config.LeaderboardConfig{FreezeAt: freezeAt}
+	assert.Equal(t, []string{"d", "c", "b", "a"}, data.revealOrder("div", cfg))
+
+	freeze := leaderboardFreeze{
+		FreezeAt:   freezeAt,
+		Revealed:   map[string]bool{"d": true},
+		TotalTeams: 4,
+		viewer:     "b",
+	}
+	assert.True(t, freeze.IsFrozen())
+
+	tests := []struct {
+		team    string
+		t       time.Time
+		visible bool
+	}{
+		{"a", before, true},
+		{"a", after, false},
+		{"b", after, true}, // the viewer
+		{"d", after, true}, // revealed
+		{"c", freezeAt, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.visible, freeze.isVisible(test.team, test.t), "%s at %v", test.team, test.t)
+	}
+
+	assert.Equal(t, 1, data.countSolves("div", "p/part1", freeze.isVisible), "solves after the freeze must be hidden")
+
+	freeze.Revealed = map[string]bool{"a": true, "b": true, "c": true, "d": true}
+	assert.False(t, freeze.IsFrozen())
+	assert.True(t, leaderboardFreeze{}.isVisible("a", after), "an unfrozen leaderboard shows everything")
+}
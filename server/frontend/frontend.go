@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"dev.acmcsuf.com/march-madness-2024/internal/clock"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/dustin/go-humanize"
 	"github.com/dustin/go-humanize/english"
@@ -31,8 +32,10 @@ func (c ComponentContext) IsAuthenticated() bool {
 // Markdown is a goldmark instance.
 var Markdown = goldmark.New()
 
-// NewTemplater returns a new templater with the given filesystem.
-func NewTemplater(fs fs.FS) *tmplutil.Templater {
+// NewTemplater returns a new templater with the given filesystem. The now
+// function of templates tells the time of the given clock, and clockSpeed how
+// many times faster than real time it runs.
+func NewTemplater(fs fs.FS, clock clock.Clock) *tmplutil.Templater {
 	t := &tmplutil.Templater{
 		FileSystem: fs,
 		Includes: map[string]string{
@@ -43,6 +46,13 @@ func NewTemplater(fs fs.FS) *tmplutil.Templater {
 		Functions: joinFuncMaps(
 			sprig.FuncMap(),
 			template.FuncMap{
+				"now": clock.Now,
+				"clockSpeed": func() float64 {
+					if c, ok := clock.(interface{ Speed() float64 }); ok {
+						return c.Speed()
+					}
+					return 1
+				},
 				"rfc3339": func(t time.Time) string {
 					return t.Format(time.RFC3339)
 				},
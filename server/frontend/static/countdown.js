@@ -1,3 +1,19 @@
+// The server may run on a simulated clock during rehearsals, so countdowns
+// follow the server time rendered into the page instead of the browser's.
+const serverTime = document.querySelector('meta[name="server-time"]');
+const loadedAt = Date.now();
+
+// now() -> number
+// now returns the current server time in milliseconds. It falls back to the
+// browser's time if the page has no server time.
+export function now() {
+  if (!serverTime) {
+    return Date.now();
+  }
+  const speed = Number(serverTime.dataset.speed) || 1;
+  return Number(serverTime.content) + (Date.now() - loadedAt) * speed;
+}
+
 // start(time: HTMLTimeElement, target: Date, formatDuration: (number) => string) -> void
 // start starts a countdown timer that updates the text content of the
 // given element with the result of calling formatDuration with the remaining
@@ -7,8 +23,7 @@ export function start(time, formatDuration, done = () => {}) {
   let id;
   const target = new Date(time.getAttribute("datetime")).getTime();
   const update = () => {
-    const now = new Date().getTime();
-    const distance = target - now;
+    const distance = target - now();
     time.textContent = formatDuration(distance);
 
     if (distance <= 0) {
@@ -38,10 +53,10 @@ export function formatDurationString(duration) {
   const s = Math.floor((duration % (1000 * 60)) / 1000);
 
   if (h > 0) {
-    time.textContent = `${h}h ${m}m ${s}s`;
+    return `${h}h ${m}m ${s}s`;
   } else if (m > 0) {
-    time.textContent = `${m}m ${s}s`;
+    return `${m}m ${s}s`;
   } else {
-    time.textContent = `${s}s`;
+    return `${s}s`;
   }
 }
@@ -0,0 +1,40 @@
+package server
+
+// PointsSource is the type of source that an entry in the points ledger came
+// from.
+type PointsSource string
+
+const (
+	// PointsFromSolve is awarded for solving a problem part. Adjustments to
+	// it, such as from solve count scaling or rescoring, share the source.
+	PointsFromSolve PointsSource = "solve"
+	// PointsFromPenalty is deducted for incorrect attempts made before a
+	// solve.
+	PointsFromPenalty PointsSource = "penalty"
+	// PointsFromFirstSolve is awarded as a first solve bonus.
+	PointsFromFirstSolve PointsSource = "first_solve"
+	// PointsFromVoid removes or credits points of a voided problem part.
+	PointsFromVoid PointsSource = "void"
+	// PointsFromHackathon is awarded to the hackathon winners.
+	PointsFromHackathon PointsSource = "hackathon"
+	// PointsFromAdmin is awarded manually by an admin. Its entries are told
+	// apart by their reason.
+	PointsFromAdmin PointsSource = "admin"
+)
+
+// Label returns a human-readable name of the source. It is also the reason of
+// the entries that the server adds.
+func (s PointsSource) Label() string {
+	switch s {
+	case PointsFromSolve:
+		return "week of code"
+	case PointsFromPenalty:
+		return "wrong answer penalty"
+	case PointsFromFirstSolve:
+		return "first solve bonus"
+	case PointsFromVoid:
+		return "voided problem"
+	default:
+		return string(s)
+	}
+}
@@ -0,0 +1,187 @@
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CooldownScope determines which incorrect attempts count towards a
+// cooldown.
+type CooldownScope string
+
+const (
+	// CooldownPerPart counts the incorrect attempts of the team on the part
+	// being submitted. This is the default.
+	CooldownPerPart CooldownScope = "part"
+	// CooldownPerTeam counts the incorrect attempts of the team on both parts
+	// of the problem.
+	CooldownPerTeam CooldownScope = "team"
+	// CooldownPerUser counts the incorrect attempts of the submitting user on
+	// the part being submitted, so that each team member has their own
+	// cooldown.
+	CooldownPerUser CooldownScope = "user"
+)
+
+// IsValid returns true if the scope is known.
+func (s CooldownScope) IsValid() bool {
+	switch s {
+	case CooldownPerPart, CooldownPerTeam, CooldownPerUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// CooldownPolicy describes how long a team has to wait before submitting
+// again after incorrect attempts. Once Threshold incorrect attempts have been
+// made, every further attempt has to wait Base * Multiplier * n after the last
+// one, where n is the number of attempts past the threshold, up to Max.
+//
+// Zero fields are unset and are filled in by [CooldownPolicy.Or]. Disabled and
+// Threshold are pointers so that false and 0 can override a fallback.
+type CooldownPolicy struct {
+	// Disabled disables the cooldown entirely.
+	Disabled *bool
+	// Threshold is the number of incorrect attempts allowed before the
+	// cooldown kicks in.
+	Threshold *int
+	// Multiplier scales the cooldown for each attempt past the threshold.
+	Multiplier float64
+	// Base is the cooldown of the first attempt past the threshold before
+	// the multiplier is applied.
+	Base time.Duration
+	// Max is the maximum cooldown.
+	Max time.Duration
+	// Scope determines which incorrect attempts are counted.
+	Scope CooldownScope
+}
+
+// DefaultCooldownPolicy is the cooldown policy used for unset fields.
+var DefaultCooldownPolicy = CooldownPolicy{
+	Threshold:  ptrTo(2),
+	Multiplier: 2,
+	Base:       30 * time.Second,
+	Max:        5 * time.Minute,
+	Scope:      CooldownPerPart,
+}
+
+// Or returns the policy with its unset fields taken from fallback.
+func (p CooldownPolicy) Or(fallback CooldownPolicy) CooldownPolicy {
+	if p.Disabled == nil {
+		p.Disabled = fallback.Disabled
+	}
+	if p.Threshold == nil {
+		p.Threshold = fallback.Threshold
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = fallback.Multiplier
+	}
+	if p.Base == 0 {
+		p.Base = fallback.Base
+	}
+	if p.Max == 0 {
+		p.Max = fallback.Max
+	}
+	if p.Scope == "" {
+		p.Scope = fallback.Scope
+	}
+	return p
+}
+
+// Validate returns an error if the policy is invalid.
+func (p CooldownPolicy) Validate() error {
+	if (p.Threshold != nil && *p.Threshold < 0) || p.Multiplier < 0 || p.Base < 0 || p.Max < 0 {
+		return fmt.Errorf("cooldown policy must not be negative")
+	}
+	if p.Scope != "" && !p.Scope.IsValid() {
+		return fmt.Errorf("invalid cooldown scope %q", p.Scope)
+	}
+	return nil
+}
+
+// CooldownEnd calculates the end of the cooldown period given the number of
+// counted incorrect attempts and the time of the last one. If there is no
+// cooldown, the zero time is returned.
+func (p CooldownPolicy) CooldownEnd(attempts int, lastSubmitted time.Time) time.Time {
+	var threshold int
+	if p.Threshold != nil {
+		threshold = *p.Threshold
+	}
+	if (p.Disabled != nil && *p.Disabled) || attempts < threshold {
+		return time.Time{}
+	}
+
+	n := attempts - threshold + 1
+	cooldown := time.Duration(float64(p.Base) * p.Multiplier * float64(n))
+	if p.Max > 0 {
+		cooldown = min(cooldown, p.Max)
+	}
+
+	return lastSubmitted.Add(cooldown)
+}
+
+type cooldownPolicyJSON struct {
+	Disabled   *bool         `json:"disabled,omitempty"`
+	Threshold  *int          `json:"threshold,omitempty"`
+	Multiplier float64       `json:"multiplier,omitempty"`
+	Base       string        `json:"base,omitempty"`
+	Max        string        `json:"max,omitempty"`
+	Scope      CooldownScope `json:"scope,omitempty"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (p CooldownPolicy) MarshalJSON() ([]byte, error) {
+	v := cooldownPolicyJSON{
+		Disabled:   p.Disabled,
+		Threshold:  p.Threshold,
+		Multiplier: p.Multiplier,
+		Scope:      p.Scope,
+	}
+	if p.Base != 0 {
+		v.Base = p.Base.String()
+	}
+	if p.Max != 0 {
+		v.Max = p.Max.String()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. For backwards compatibility, a
+// plain duration string sets the base cooldown.
+func (p *CooldownPolicy) UnmarshalJSON(b []byte) error {
+	var v cooldownPolicyJSON
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte(`"`)) {
+		if err := json.Unmarshal(b, &v.Base); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*p = CooldownPolicy{
+		Disabled:   v.Disabled,
+		Threshold:  v.Threshold,
+		Multiplier: v.Multiplier,
+		Scope:      v.Scope,
+	}
+
+	var err error
+	if v.Base != "" {
+		if p.Base, err = time.ParseDuration(v.Base); err != nil {
+			return fmt.Errorf("invalid cooldown base: %w", err)
+		}
+	}
+	if v.Max != "" {
+		if p.Max, err = time.ParseDuration(v.Max); err != nil {
+			return fmt.Errorf("invalid cooldown max: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
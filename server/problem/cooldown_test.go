@@ -0,0 +1,55 @@
+package problem
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestCooldownPolicy(t *testing.T) {
+	last := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	policy := DefaultCooldownPolicy
+
+	assert.Zero(t, policy.CooldownEnd(1, last))
+	assert.Equal(t, last.Add(time.Minute), policy.CooldownEnd(2, last))
+	assert.Equal(t, last.Add(2*time.Minute), policy.CooldownEnd(3, last))
+	assert.Equal(t, last.Add(5*time.Minute), policy.CooldownEnd(100, last))
+
+	policy = CooldownPolicy{Disabled: ptrTo(true)}.Or(DefaultCooldownPolicy)
+	assert.Zero(t, policy.CooldownEnd(100, last))
+
+	// Explicit zero values override the fallback.
+	policy = CooldownPolicy{Disabled: ptrTo(false), Threshold: ptrTo(0)}.Or(policy)
+	assert.Equal(t, last.Add(2*time.Minute), policy.CooldownEnd(1, last))
+}
+
+func TestCooldownPolicyJSON(t *testing.T) {
+	var cfg ProblemConfig
+	err := json.Unmarshal([]byte(`{"cooldown": {"base": "10s", "scope": "user"}}`), &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, CooldownPolicy{Base: 10 * time.Second, Scope: CooldownPerUser}, cfg.Cooldown)
+
+	policy := cfg.Cooldown.Or(DefaultCooldownPolicy)
+	assert.Equal(t, 2, *policy.Threshold)
+	assert.Equal(t, 5*time.Minute, policy.Max)
+
+	b, err := json.Marshal(cfg.Cooldown)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"base":"10s","scope":"user"}`, string(b))
+
+	var base CooldownPolicy
+	assert.NoError(t, json.Unmarshal([]byte(`"45s"`), &base))
+	assert.Equal(t, CooldownPolicy{Base: 45 * time.Second}, base)
+
+	var zero CooldownPolicy
+	assert.NoError(t, json.Unmarshal([]byte(`{"threshold": 0, "disabled": false}`), &zero))
+	assert.Equal(t, CooldownPolicy{Disabled: ptrTo(false), Threshold: ptrTo(0)}, zero)
+
+	b, err = json.Marshal(zero)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"disabled":false,"threshold":0}`, string(b))
+
+	assert.Error(t, CooldownPolicy{Scope: "everyone"}.Validate())
+}
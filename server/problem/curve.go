@@ -0,0 +1,100 @@
+package problem
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ScoringCurveType is the shape of a [ScoringCurve].
+type ScoringCurveType string
+
+const (
+	// LinearCurve interpolates linearly between the points of the curve.
+	LinearCurve ScoringCurveType = "linear"
+	// ExponentialCurve halves the points every half-life.
+	ExponentialCurve ScoringCurveType = "exponential"
+)
+
+// ScoringCurve is a scoring function defined in config rather than in code.
+// It scales the points of a part by the hours since the problem started, like
+// the time-based scoring versions do.
+type ScoringCurve struct {
+	Type ScoringCurveType `json:"type"`
+	// Points are the points of a linear curve, ordered by their hours. The
+	// percentage before the first point is that of the first point, and the
+	// percentage after the last point is that of the last point.
+	Points []ScoringCurvePoint `json:"points,omitempty"`
+	// HalfLifeHours is the half-life of an exponential curve.
+	HalfLifeHours float64 `json:"half_life_hours,omitempty"`
+	// FloorPercent is the minimum percentage of the points that a solve is
+	// awarded, no matter how late.
+	FloorPercent float64 `json:"floor_percent,omitempty"`
+}
+
+// ScoringCurvePoint is a point of a linear scoring curve.
+type ScoringCurvePoint struct {
+	Hours   float64 `json:"hours"`
+	Percent float64 `json:"percent"`
+}
+
+// Validate returns an error if the curve is invalid.
+func (c *ScoringCurve) Validate() error {
+	switch c.Type {
+	case LinearCurve:
+		if len(c.Points) == 0 {
+			return fmt.Errorf("linear curve needs at least one point")
+		}
+		for i, p := range c.Points {
+			if p.Hours < 0 {
+				return fmt.Errorf("point %d: hours must not be negative", i+1)
+			}
+			if i > 0 && p.Hours <= c.Points[i-1].Hours {
+				return fmt.Errorf("point %d: hours must be increasing", i+1)
+			}
+			if p.Percent < 0 || p.Percent > 100 {
+				return fmt.Errorf("point %d: percent must be between 0 and 100", i+1)
+			}
+		}
+	case ExponentialCurve:
+		if c.HalfLifeHours <= 0 {
+			return fmt.Errorf("exponential curve needs a positive half-life")
+		}
+	default:
+		return fmt.Errorf("unknown curve type %q", c.Type)
+	}
+	if c.FloorPercent < 0 || c.FloorPercent > 100 {
+		return fmt.Errorf("floor percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// scale returns the fraction of the points awarded for a solve at t.
+func (c *ScoringCurve) scale(t, startedAt time.Time) float64 {
+	hours := t.Sub(startedAt).Hours()
+
+	var f float64
+	switch c.Type {
+	case LinearCurve:
+		f = c.interpolate(hours) / 100
+	case ExponentialCurve:
+		f = math.Pow(0.5, math.Max(hours, 0)/c.HalfLifeHours)
+	}
+
+	return clamp(f, c.FloorPercent/100, 1)
+}
+
+func (c *ScoringCurve) interpolate(hours float64) float64 {
+	points := c.Points
+	if hours <= points[0].Hours {
+		return points[0].Percent
+	}
+	for i := 1; i < len(points); i++ {
+		if hours <= points[i].Hours {
+			a, b := points[i-1], points[i]
+			x := (hours - a.Hours) / (b.Hours - a.Hours)
+			return a.Percent + x*(b.Percent-a.Percent)
+		}
+	}
+	return points[len(points)-1].Percent
+}
@@ -0,0 +1,57 @@
+package problem
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestScoringCurve(t *testing.T) {
+	start := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time {
+		return start.Add(time.Duration(hours * float64(time.Hour)))
+	}
+
+	var linear ProblemConfig
+	err := json.Unmarshal([]byte(`{
+		"points_per_part": 200,
+		"scoring_curve": {
+			"type": "linear",
+			"points": [{"hours": 1, "percent": 100}, {"hours": 5, "percent": 60}],
+			"floor_percent": 75
+		}
+	}`), &linear)
+	assert.NoError(t, err)
+	assert.NoError(t, linear.Validate())
+
+	assert.Equal(t, 200.0, linear.ScalePoints(at(0), start))
+	assert.Equal(t, 200.0, linear.ScalePoints(at(1), start))
+	assert.Equal(t, 160.0, linear.ScalePoints(at(3), start))
+	assert.Equal(t, 150.0, linear.ScalePoints(at(100), start), "points must not go below the floor")
+
+	exponential := ProblemConfig{
+		ScoringCurve: &ScoringCurve{Type: ExponentialCurve, HalfLifeHours: 6},
+	}
+	assert.NoError(t, exponential.Validate())
+	assert.Equal(t, 100.0, exponential.ScalePoints(at(0), start))
+	assert.Equal(t, 50.0, exponential.ScalePoints(at(6), start))
+	assert.Equal(t, 25.0, exponential.ScalePoints(at(12), start))
+
+	invalid := []ScoringCurve{
+		{Type: "quadratic"},
+		{Type: LinearCurve},
+		{Type: LinearCurve, Points: []ScoringCurvePoint{{2, 100}, {1, 50}}},
+		{Type: LinearCurve, Points: []ScoringCurvePoint{{0, 120}}},
+		{Type: ExponentialCurve},
+		{Type: ExponentialCurve, HalfLifeHours: 6, FloorPercent: -1},
+	}
+	for _, curve := range invalid {
+		assert.Error(t, curve.Validate(), "curve = %+v", curve)
+	}
+
+	withVersion := exponential
+	withVersion.ScoringVersion = V2ScoreScaling
+	assert.Error(t, withVersion.Validate(), "curve and version must not be combined")
+}
@@ -0,0 +1,129 @@
+package problem
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduleOverrideAction is the kind of action a schedule override performs.
+type ScheduleOverrideAction string
+
+const (
+	// DelayRelease delays the release of a single problem.
+	DelayRelease ScheduleOverrideAction = "delay"
+	// PauseReleases holds back every problem that has not been released yet.
+	PauseReleases ScheduleOverrideAction = "pause"
+	// ResumeReleases undoes PauseReleases. Problems that were held back are
+	// released immediately if their time has passed.
+	ResumeReleases ScheduleOverrideAction = "resume"
+	// ForceRelease releases a problem and all problems before it immediately,
+	// even if releases are paused.
+	ForceRelease ScheduleOverrideAction = "release"
+)
+
+// IsValid returns true if the action is known.
+func (a ScheduleOverrideAction) IsValid() bool {
+	switch a {
+	case DelayRelease, PauseReleases, ResumeReleases, ForceRelease:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScheduleOverride is a change to the release schedule made at runtime.
+type ScheduleOverride struct {
+	// Action is the action of the override.
+	Action ScheduleOverrideAction
+	// At is the time at which the override was made.
+	At time.Time
+	// Problem is the index of the problem that the override applies to. It is
+	// only used by DelayRelease and ForceRelease.
+	Problem int
+	// Delay is the duration to delay the problem by. It is only used by
+	// DelayRelease. Delays for the same problem add up.
+	Delay time.Duration
+}
+
+// scheduleOverrides is the result of applying a list of overrides in order.
+type scheduleOverrides struct {
+	pauses []schedulePause
+	delays map[int]time.Duration
+	forced map[int]time.Time
+}
+
+type schedulePause struct {
+	start time.Time
+	end   time.Time // zero if still paused
+}
+
+func newScheduleOverrides(overrides []ScheduleOverride) (*scheduleOverrides, error) {
+	overrides = append([]ScheduleOverride(nil), overrides...)
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrides[i].At.Before(overrides[j].At)
+	})
+
+	o := &scheduleOverrides{
+		delays: make(map[int]time.Duration),
+		forced: make(map[int]time.Time),
+	}
+
+	for _, override := range overrides {
+		switch override.Action {
+		case DelayRelease:
+			o.delays[override.Problem] += override.Delay
+		case PauseReleases:
+			if !o.isPaused() {
+				o.pauses = append(o.pauses, schedulePause{start: override.At})
+			}
+		case ResumeReleases:
+			if o.isPaused() {
+				o.pauses[len(o.pauses)-1].end = override.At
+			}
+		case ForceRelease:
+			if t, ok := o.forced[override.Problem]; !ok || override.At.Before(t) {
+				o.forced[override.Problem] = override.At
+			}
+		default:
+			return nil, fmt.Errorf("unknown schedule override action %q", override.Action)
+		}
+	}
+
+	return o, nil
+}
+
+func (o *scheduleOverrides) isPaused() bool {
+	return len(o.pauses) > 0 && o.pauses[len(o.pauses)-1].end.IsZero()
+}
+
+// apply applies the overrides to the scheduled release time t of the problem
+// at index i. It returns false if the problem is held back by an ongoing
+// pause.
+func (o *scheduleOverrides) apply(i int, t time.Time) (time.Time, bool) {
+	t = t.Add(o.delays[i])
+
+	held := false
+	for _, pause := range o.pauses {
+		if t.Before(pause.start) {
+			continue
+		}
+		if pause.end.IsZero() {
+			held = true
+			break
+		}
+		if t.Before(pause.end) {
+			t = pause.end
+		}
+	}
+
+	// Forcing a problem also forces every problem before it.
+	for j, forced := range o.forced {
+		if j >= i && (held || forced.Before(t)) {
+			t = forced
+			held = false
+		}
+	}
+
+	return t, !held
+}
@@ -2,12 +2,18 @@ package problem
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"io/fs"
 	"log/slog"
 	"math"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -17,11 +23,23 @@ import (
 
 // ModuleConfig is a module that points to a problem.
 type ModuleConfig struct {
+	// ID is the stable ID of the problem. Submissions and points are recorded
+	// using it, so it must not change once the competition has started. If
+	// empty, the README path is used for backwards compatibility.
+	ID      string `json:"id,omitempty"`
 	Command string `json:"cmd"`
 	README  string `json:"readme"`
 	ProblemConfig
 }
 
+// ProblemID returns the ID of the problem that the module points to.
+func (m ModuleConfig) ProblemID() string {
+	if m.ID != "" {
+		return m.ID
+	}
+	return m.README
+}
+
 // ProblemConfig contains optional configuration for a problem.
 type ProblemConfig struct {
 	// PointsPerPart is the number of points awarded for each part of the problem.
@@ -29,6 +47,28 @@ type ProblemConfig struct {
 	PointsPerPart float64 `json:"points_per_part,omitempty"`
 	// ScoringVersion is the version of the scoring function.
 	ScoringVersion ScoringVersion `json:"scoring_version,omitempty"`
+	// ScoringCurve, if set, is used as the scoring function instead of a
+	// built-in scoring version.
+	ScoringCurve *ScoringCurve `json:"scoring_curve,omitempty"`
+	// FirstSolveBonuses are the bonus points awarded to the first teams to
+	// solve each part, in order. For example, [30, 20, 10] awards 30 points
+	// to the first team, 20 to the second and 10 to the third.
+	FirstSolveBonuses []float64 `json:"first_solve_bonuses,omitempty"`
+	// WrongAnswerPenalty is the penalty for each incorrect attempt made before
+	// a part is solved. There is no penalty by default.
+	WrongAnswerPenalty WrongAnswerPenalty `json:"wrong_answer_penalty"`
+	// Cooldown is the cooldown policy for incorrect attempts. Unset fields
+	// default to [DefaultCooldownPolicy].
+	Cooldown CooldownPolicy `json:"cooldown"`
+	// DecayFromFirstOpen measures the score decay of each team from when one
+	// of its members first opened the problem or fetched its input, instead
+	// of from the problem's release. This is fairer to teams in other time
+	// zones.
+	DecayFromFirstOpen bool `json:"decay_from_first_open,omitempty"`
+	// DecayStartCapHours caps how many hours after the release a team's
+	// decay may start when DecayFromFirstOpen is set, so that the window
+	// can't be stretched forever. It defaults to [DefaultDecayStartCap].
+	DecayStartCapHours float64 `json:"decay_start_cap_hours,omitempty"`
 }
 
 // Problem is a problem that can be solved.
@@ -51,6 +91,7 @@ func NewProblem(id string, desc ProblemDescription, runner Runner, cfg ProblemCo
 	if cfg.ScoringVersion == 0 {
 		cfg.ScoringVersion = latestScoreScalingVersion
 	}
+	cfg.Cooldown = cfg.Cooldown.Or(DefaultCooldownPolicy)
 	return Problem{
 		ID:            id,
 		Description:   desc,
@@ -59,10 +100,48 @@ func NewProblem(id string, desc ProblemDescription, runner Runner, cfg ProblemCo
 	}
 }
 
+// Validate returns an error if the options of the config are invalid or
+// can't be combined.
+func (c ProblemConfig) Validate() error {
+	if c.ScoringVersion != 0 && !c.ScoringVersion.IsValid() {
+		return fmt.Errorf("invalid scoring version %d", c.ScoringVersion)
+	}
+
+	if c.ScoringCurve != nil {
+		if c.ScoringVersion != 0 {
+			return fmt.Errorf("scoring curve cannot be used with a scoring version")
+		}
+		if err := c.ScoringCurve.Validate(); err != nil {
+			return fmt.Errorf("invalid scoring curve: %w", err)
+		}
+	}
+
+	if err := c.WrongAnswerPenalty.validate(c.ScoringVersion); err != nil {
+		return fmt.Errorf("invalid wrong answer penalty: %w", err)
+	}
+
+	if c.DecayStartCapHours < 0 {
+		return fmt.Errorf("decay start cap must not be negative")
+	}
+	if c.DecayFromFirstOpen && c.ScoringVersion.IsSolveCountScaling() {
+		return fmt.Errorf("decay from first open cannot be used with solve count scaling")
+	}
+
+	if err := c.Cooldown.Validate(); err != nil {
+		return fmt.Errorf("invalid cooldown: %w", err)
+	}
+
+	return nil
+}
+
 // NewProblemFromModule creates a new problem from a problem module.
 func NewProblemFromModule(module ModuleConfig, logger *slog.Logger) (Problem, error) {
 	var z Problem
 
+	if err := module.ProblemConfig.Validate(); err != nil {
+		return z, err
+	}
+
 	description, err := ParseProblemDescriptionFile(module.README)
 	if err != nil {
 		return z, fmt.Errorf("failed to parse README file at %q: %w", module.README, err)
@@ -73,7 +152,12 @@ func NewProblemFromModule(module ModuleConfig, logger *slog.Logger) (Problem, er
 		return z, fmt.Errorf("failed to create command runner %q: %w", module.Command, err)
 	}
 
-	return NewProblem(module.README, description, runner, module.ProblemConfig), nil
+	runner.moduleHash, err = hashModuleDir(filepath.Dir(module.README))
+	if err != nil {
+		return z, fmt.Errorf("failed to hash problem module of %q: %w", module.README, err)
+	}
+
+	return NewProblem(module.ProblemID(), description, runner, module.ProblemConfig), nil
 }
 
 // Runner is a problem runner.
@@ -90,6 +174,9 @@ type Runner interface {
 type CommandRunner struct {
 	logger  *slog.Logger
 	command string
+	// moduleHash is the hash of the files of the problem module, if the runner
+	// was created from one. See [hashModuleDir].
+	moduleHash string
 }
 
 // NewCommandRunner creates a new CommandRunner from a command.
@@ -247,6 +334,84 @@ func CacheAllProblems(problems []Problem, logger *slog.Logger) {
 	}
 }
 
+// CacheProblems is like [CacheAllProblems], but it reuses the caches of the
+// previous problems whose input generators did not change. This is used when
+// reloading problems so that unchanged problems keep their cached inputs.
+func CacheProblems(problems, previous []Problem, logger *slog.Logger) {
+	for i := range problems {
+		if cached := findCachedRunner(previous, problems[i]); cached != nil {
+			problems[i].Runner = cached
+			continue
+		}
+		problems[i].Runner = NewCachedRunner(logger, problems[i])
+	}
+}
+
+func findCachedRunner(previous []Problem, problem Problem) *CachedRunner {
+	for _, p := range previous {
+		cached, ok := p.Runner.(*CachedRunner)
+		if !ok || p.ID != problem.ID {
+			continue
+		}
+		if sameRunner(cached.runner, problem.Runner) {
+			return cached
+		}
+	}
+	return nil
+}
+
+// sameRunner returns true if both runners are known to generate the same
+// inputs and solutions. Command runners are the same if they run the same
+// command and the files of their problem modules didn't change. Other runners
+// can't be inspected, so they are only the same if they are the same runner.
+func sameRunner(a, b Runner) bool {
+	ca, ok1 := a.(*CommandRunner)
+	cb, ok2 := b.(*CommandRunner)
+	if ok1 && ok2 {
+		return ca.command == cb.command && ca.moduleHash == cb.moduleHash
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta != nil && ta == tb && ta.Comparable() && a == b
+}
+
+// hashModuleDir hashes the files in the directory of a problem module, which
+// is the directory of its README. The input generator of a module is expected
+// to live there, so a different hash means that it may have been edited.
+// Python's bytecode caches are skipped since running the generator writes them.
+func hashModuleDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "__pycache__" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", rel, len(b))
+		h.Write(b)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // StringToSeed converts a string to a seed.
 // It ensures that the seed is small enough that it is reasonable enough to
 // cache the input.
@@ -2,6 +2,8 @@ package problem
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -75,3 +77,81 @@ func TestStringToSeed(t *testing.T) {
 		}
 	}
 }
+
+func TestCacheProblems(t *testing.T) {
+	logger := slogt.New(t)
+
+	newProblem := func(id, cmd string) Problem {
+		runner, err := NewCommandRunner(logger, cmd)
+		assert.NoError(t, err)
+		return NewProblem(id, ProblemDescription{}, runner, ProblemConfig{})
+	}
+
+	previous := []Problem{
+		newProblem("a", "cmd-a"),
+		newProblem("b", "cmd-b"),
+	}
+	CacheAllProblems(previous, logger)
+
+	problems := []Problem{
+		newProblem("a", "cmd-a"),
+		newProblem("b", "cmd-b --changed"),
+		newProblem("c", "cmd-c"),
+	}
+	CacheProblems(problems, previous, logger)
+
+	assert.True(t, problems[0].Runner == previous[0].Runner, "unchanged problem must reuse its cache")
+	assert.True(t, problems[1].Runner != previous[1].Runner, "changed problem must not reuse its cache")
+	_, ok := problems[2].Runner.(*CachedRunner)
+	assert.True(t, ok, "new problem must be cached")
+
+	// Other runners are only reused if they are the same runner.
+	other := &CachedRunner{}
+	previous = []Problem{NewProblem("a", ProblemDescription{}, other, ProblemConfig{})}
+	CacheAllProblems(previous, logger)
+	problems = []Problem{
+		NewProblem("a", ProblemDescription{}, other, ProblemConfig{}),
+		NewProblem("a", ProblemDescription{}, &CachedRunner{}, ProblemConfig{}),
+	}
+	CacheProblems(problems, previous, logger)
+	assert.True(t, problems[0].Runner == previous[0].Runner, "same runner must reuse its cache")
+	assert.True(t, problems[1].Runner != previous[0].Runner, "other runner must not reuse its cache")
+}
+
+func TestCacheProblemsEditedGenerator(t *testing.T) {
+	ctx := context.Background()
+	logger := slogt.New(t)
+
+	dir := t.TempDir()
+	readme := filepath.Join(dir, "README.md")
+	generator := filepath.Join(dir, "generate.sh")
+	assert.NoError(t, os.WriteFile(readme, []byte("# Problem\n\n## Part 1\n\n## Part 2\n"), 0644))
+
+	module := ModuleConfig{
+		ID:      "problem",
+		Command: "sh " + generator,
+		README:  readme,
+	}
+	load := func(input string, previous []Problem) []Problem {
+		assert.NoError(t, os.WriteFile(generator, []byte("echo "+input+"\n"), 0644))
+		p, err := NewProblemFromModule(module, logger)
+		assert.NoError(t, err)
+		problems := []Problem{p}
+		CacheProblems(problems, previous, logger)
+		return problems
+	}
+
+	problems := load("old", nil)
+	input, err := problems[0].Input(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "old", input)
+
+	reloaded := load("old", problems)
+	assert.True(t, reloaded[0].Runner == problems[0].Runner, "unchanged generator must reuse its cache")
+
+	// The command stays the same, but the script that it runs is edited.
+	reloaded = load("new", reloaded)
+	input, err = reloaded[0].Input(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", input, "edited generator must not serve cached inputs")
+}
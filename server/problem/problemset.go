@@ -1,28 +1,92 @@
 package problem
 
-import "time"
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/clock"
+)
 
 // ProblemSet is a set of problems. It is a collection of problems that
 // can be solved in any order. It supports timed releases of problems.
 type ProblemSet struct {
-	problems []Problem
-	schedule *ProblemReleaseSchedule
-	now      func() time.Time
+	problems  []Problem
+	schedule  *ProblemReleaseSchedule
+	overrides atomic.Pointer[scheduleOverrides]
+	now       func() time.Time
 }
 
 // ProblemReleaseSchedule is the schedule for releasing problems.
+//
+// By default, problems are released at a fixed interval starting at
+// StartReleaseAt. Either ReleaseTimes or ReleaseOffsets may be given to
+// release each problem at an explicit time instead.
 type ProblemReleaseSchedule struct {
 	// StartReleaseAt is the time at which the first problem is released.
 	StartReleaseAt time.Time
 	// ReleaseEvery is the duration between releases.
 	ReleaseEvery time.Duration
+	// ReleaseTimes, if not empty, is the release time of each problem.
+	ReleaseTimes []time.Time
+	// ReleaseOffsets, if not empty, is the release time of each problem
+	// relative to StartReleaseAt.
+	ReleaseOffsets []time.Duration
+	// ClosesAt, if not zero, is the time at which the competition ends.
+	// Submissions after this time are practice attempts and award no points.
+	ClosesAt time.Time
+}
+
+// IsRegular returns true if problems are released at a fixed interval, i.e.
+// no explicit release times are given.
+func (s *ProblemReleaseSchedule) IsRegular() bool {
+	return len(s.ReleaseTimes) == 0 && len(s.ReleaseOffsets) == 0
+}
+
+// ReleaseTime returns the time at which the problem at the given index is
+// released.
+func (s *ProblemReleaseSchedule) ReleaseTime(i int) time.Time {
+	switch {
+	case len(s.ReleaseTimes) > 0:
+		return s.ReleaseTimes[i]
+	case len(s.ReleaseOffsets) > 0:
+		return s.StartReleaseAt.Add(s.ReleaseOffsets[i])
+	default:
+		return s.StartReleaseAt.Add(time.Duration(i) * s.ReleaseEvery)
+	}
+}
+
+// Validate checks that the schedule is valid for the given number of
+// problems.
+func (s *ProblemReleaseSchedule) Validate(n int) error {
+	if len(s.ReleaseTimes) > 0 && len(s.ReleaseOffsets) > 0 {
+		return fmt.Errorf("cannot have both release times and release offsets")
+	}
+	if !s.ClosesAt.IsZero() && s.ClosesAt.Before(s.ReleaseTime(0)) {
+		return fmt.Errorf("competition closes before the first problem is released")
+	}
+	if s.IsRegular() {
+		if s.ReleaseEvery <= 0 && n > 1 {
+			return fmt.Errorf("release interval must be positive")
+		}
+		return nil
+	}
+	if l := max(len(s.ReleaseTimes), len(s.ReleaseOffsets)); l != n {
+		return fmt.Errorf("schedule has %d release times for %d problems", l, n)
+	}
+	for i := 1; i < n; i++ {
+		if s.ReleaseTime(i).Before(s.ReleaseTime(i - 1)) {
+			return fmt.Errorf("problem %d is released before problem %d", i+1, i)
+		}
+	}
+	return nil
 }
 
 // NewProblemSet creates a new problem set.
 func NewProblemSet(problems []Problem) *ProblemSet {
 	return &ProblemSet{
 		problems: problems,
-		now:      time.Now,
+		now:      clock.System.Now,
 	}
 }
 
@@ -31,32 +95,72 @@ func NewProblemSetWithSchedule(problems []Problem, schedule *ProblemReleaseSched
 	return &ProblemSet{
 		problems: problems,
 		schedule: schedule,
-		now:      time.Now,
+		now:      clock.System.Now,
 	}
 }
 
+// SetClock sets the clock used to tell which problems are released. It must be
+// called before the problem set is used.
+func (p *ProblemSet) SetClock(c clock.Clock) {
+	p.now = c.Now
+}
+
 // Schedule returns the release schedule of the problem set. If the problem set
 // does not have a release schedule, it returns nil.
 func (p *ProblemSet) Schedule() *ProblemReleaseSchedule {
 	return p.schedule
 }
 
+// SetScheduleOverrides replaces the runtime overrides applied on top of the
+// release schedule. The overrides are applied in chronological order.
+func (p *ProblemSet) SetScheduleOverrides(overrides []ScheduleOverride) error {
+	o, err := newScheduleOverrides(overrides)
+	if err != nil {
+		return err
+	}
+	p.overrides.Store(o)
+	return nil
+}
+
+// IsPaused returns true if problem releases are currently paused.
+func (p *ProblemSet) IsPaused() bool {
+	o := p.overrides.Load()
+	return o != nil && o.isPaused()
+}
+
 // StartedAt returns the time at which the first problem is released. If the
 // problem set does not have a release schedule, it returns the zero time.
 func (p *ProblemSet) StartedAt() time.Time {
+	return p.ProblemStartTime(0)
+}
+
+// EndingAt returns the time at which the last problem's release period ends,
+// which is ReleaseEvery after the last problem is released. If the problem set
+// does not have a release schedule, it returns the zero time.
+func (p *ProblemSet) EndingAt() time.Time {
 	if p.schedule == nil {
 		return time.Time{}
 	}
-	return p.schedule.StartReleaseAt
+	if len(p.problems) == 0 {
+		return p.schedule.StartReleaseAt
+	}
+	return p.ProblemStartTime(len(p.problems) - 1).Add(p.schedule.ReleaseEvery)
 }
 
-// EndingAt returns the time at which the last problem is released. If the
-// problem set does not have a release schedule, it returns the zero time.
-func (p *ProblemSet) EndingAt() time.Time {
+// ClosesAt returns the time at which the competition ends. If the competition
+// never ends, it returns the zero time.
+func (p *ProblemSet) ClosesAt() time.Time {
 	if p.schedule == nil {
 		return time.Time{}
 	}
-	return p.schedule.StartReleaseAt.Add(time.Duration(len(p.problems)) * p.schedule.ReleaseEvery)
+	return p.schedule.ClosesAt
+}
+
+// IsClosed returns true if the competition has ended. Problems can still be
+// solved after the competition ends, but they award no points.
+func (p *ProblemSet) IsClosed() bool {
+	closesAt := p.ClosesAt()
+	return !closesAt.IsZero() && !p.now().Before(closesAt)
 }
 
 // Problems returns all available problems in the set.
@@ -64,6 +168,12 @@ func (p *ProblemSet) Problems() []Problem {
 	return p.problems[:p.AvailableProblems()]
 }
 
+// AllProblems returns all problems in the set, including the ones that are not
+// available yet.
+func (p *ProblemSet) AllProblems() []Problem {
+	return p.problems
+}
+
 // Problem returns the problem at the given index. If the index is accessing a
 // problem that is not available yet, it returns nil.
 func (p *ProblemSet) Problem(i int) *Problem {
@@ -75,15 +185,37 @@ func (p *ProblemSet) Problem(i int) *Problem {
 }
 
 // ProblemStartTime calculates the time at which the problem at the given index
-// was released. If the problem set does not have a release schedule, it returns
-// the zero time.
+// was released, taking schedule overrides into account. If the problem set
+// does not have a release schedule, it returns the zero time. If the problem
+// is held back by a pause, it returns the time at which releases were paused.
 func (p *ProblemSet) ProblemStartTime(i int) time.Time {
 	if p.schedule == nil {
 		return time.Time{}
 	}
-	start := p.schedule.StartReleaseAt
-	delta := time.Duration(i) * p.schedule.ReleaseEvery
-	return start.Add(delta)
+	t, _ := p.releaseTime(i)
+	return t
+}
+
+// releaseTime returns the time at which the problem at the given index is
+// released. A problem is never released before the problems preceding it.
+// It returns false if the problem is held back by an ongoing pause.
+func (p *ProblemSet) releaseTime(i int) (time.Time, bool) {
+	o := p.overrides.Load()
+	if o == nil {
+		return p.schedule.ReleaseTime(i), true
+	}
+
+	var release time.Time
+	for j := 0; j <= i; j++ {
+		t, ok := o.apply(j, p.schedule.ReleaseTime(j))
+		if !ok {
+			return o.pauses[len(o.pauses)-1].start, false
+		}
+		if t.After(release) {
+			release = t
+		}
+	}
+	return release, true
 }
 
 // TotalProblems returns the total number of problems in the set.
@@ -99,21 +231,23 @@ func (p *ProblemSet) AvailableProblems() int {
 	}
 
 	now := p.now()
-	delta := now.Sub(p.schedule.StartReleaseAt)
-	if delta < 0 {
-		// The first problem is not released yet.
-		return 0
-	}
-	n := int(delta/p.schedule.ReleaseEvery) + 1
-	if n >= len(p.problems) {
-		// All problems are released.
-		return p.TotalProblems()
+
+	// Problems are released in order, so a problem is only available once
+	// all problems before it are.
+	for i := range p.problems {
+		t, ok := p.releaseTime(i)
+		if !ok || now.Before(t) {
+			return i
+		}
 	}
-	return n
+
+	// All problems are released.
+	return p.TotalProblems()
 }
 
 // NextReleaseTime returns the time at which the next problem will be released.
-// If all problems are released, it returns the zero time.
+// If all problems are released or releases are paused, it returns the zero
+// time.
 func (p *ProblemSet) NextReleaseTime() time.Time {
 	if p.schedule == nil {
 		return time.Time{}
@@ -124,7 +258,11 @@ func (p *ProblemSet) NextReleaseTime() time.Time {
 		return time.Time{}
 	}
 
-	return p.schedule.StartReleaseAt.Add(time.Duration(n) * p.schedule.ReleaseEvery)
+	t, ok := p.releaseTime(n)
+	if !ok {
+		return time.Time{}
+	}
+	return t
 }
 
 // TimeUntilNextRelease returns the duration until the next problem is released.
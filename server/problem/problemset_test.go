@@ -0,0 +1,159 @@
+package problem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestProblemSetSchedule(t *testing.T) {
+	start := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		schedule ProblemReleaseSchedule
+		releases []time.Time
+		ending   time.Time
+	}{
+		{
+			name: "regular",
+			schedule: ProblemReleaseSchedule{
+				StartReleaseAt: start,
+				ReleaseEvery:   day,
+			},
+			releases: []time.Time{start, start.Add(day), start.Add(2 * day)},
+			ending:   start.Add(3 * day),
+		},
+		{
+			name: "times",
+			schedule: ProblemReleaseSchedule{
+				ReleaseEvery: day,
+				ReleaseTimes: []time.Time{start, start.Add(day), start.Add(4 * day)},
+			},
+			releases: []time.Time{start, start.Add(day), start.Add(4 * day)},
+			ending:   start.Add(5 * day),
+		},
+		{
+			name: "offsets",
+			schedule: ProblemReleaseSchedule{
+				StartReleaseAt: start,
+				ReleaseOffsets: []time.Duration{0, 3 * day, 3*day + time.Hour},
+			},
+			releases: []time.Time{start, start.Add(3 * day), start.Add(3*day + time.Hour)},
+			ending:   start.Add(3*day + time.Hour),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems := make([]Problem, len(test.releases))
+			set := NewProblemSetWithSchedule(problems, &test.schedule)
+			assert.NoError(t, test.schedule.Validate(len(problems)))
+
+			assert.Equal(t, test.releases[0], set.StartedAt())
+			assert.Equal(t, test.ending, set.EndingAt())
+
+			for i, release := range test.releases {
+				assert.Equal(t, release, set.ProblemStartTime(i), "problem %d start time", i)
+
+				set.now = func() time.Time { return release.Add(-time.Second) }
+				assert.Equal(t, i, set.AvailableProblems(), "before problem %d", i)
+				assert.Equal(t, release, set.NextReleaseTime(), "before problem %d", i)
+
+				set.now = func() time.Time { return release }
+				assert.Equal(t, i+1, set.AvailableProblems(), "at problem %d", i)
+			}
+
+			assert.Equal(t, time.Time{}, set.NextReleaseTime())
+		})
+	}
+}
+
+func TestProblemReleaseScheduleValidate(t *testing.T) {
+	start := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+
+	schedule := ProblemReleaseSchedule{
+		ReleaseTimes: []time.Time{start, start.Add(-time.Hour)},
+	}
+	assert.Error(t, schedule.Validate(2), "out of order release times")
+
+	schedule = ProblemReleaseSchedule{
+		ReleaseOffsets: []time.Duration{0, time.Hour},
+	}
+	assert.Error(t, schedule.Validate(3), "missing release offset")
+	assert.NoError(t, schedule.Validate(2))
+}
+
+func TestProblemSetScheduleOverrides(t *testing.T) {
+	start := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	set := NewProblemSetWithSchedule(make([]Problem, 4), &ProblemReleaseSchedule{
+		StartReleaseAt: start,
+		ReleaseEvery:   day,
+	})
+
+	at := func(t time.Time) {
+		set.now = func() time.Time { return t }
+	}
+
+	// Delay day 2 by 2 hours.
+	assert.NoError(t, set.SetScheduleOverrides([]ScheduleOverride{
+		{Action: DelayRelease, At: start, Problem: 1, Delay: 2 * time.Hour},
+	}))
+	at(start.Add(day + time.Hour))
+	assert.Equal(t, 1, set.AvailableProblems())
+	assert.Equal(t, start.Add(day+2*time.Hour), set.NextReleaseTime())
+	assert.Equal(t, start.Add(day+2*time.Hour), set.ProblemStartTime(1))
+
+	// Pause before day 3, then resume after it was supposed to come out.
+	pausedAt := start.Add(2*day - time.Hour)
+	resumedAt := start.Add(2*day + 3*time.Hour)
+	assert.NoError(t, set.SetScheduleOverrides([]ScheduleOverride{
+		{Action: PauseReleases, At: pausedAt},
+	}))
+	at(start.Add(2*day + time.Hour))
+	assert.True(t, set.IsPaused())
+	assert.Equal(t, 2, set.AvailableProblems())
+	assert.Equal(t, time.Time{}, set.NextReleaseTime())
+
+	assert.NoError(t, set.SetScheduleOverrides([]ScheduleOverride{
+		{Action: PauseReleases, At: pausedAt},
+		{Action: ResumeReleases, At: resumedAt},
+	}))
+	at(resumedAt)
+	assert.False(t, set.IsPaused())
+	assert.Equal(t, 3, set.AvailableProblems())
+	assert.Equal(t, resumedAt, set.ProblemStartTime(2))
+
+	// Force-releasing day 4 also releases day 3, even while paused.
+	releasedAt := start.Add(day)
+	assert.NoError(t, set.SetScheduleOverrides([]ScheduleOverride{
+		{Action: PauseReleases, At: start},
+		{Action: ForceRelease, At: releasedAt, Problem: 3},
+	}))
+	at(releasedAt)
+	assert.Equal(t, 4, set.AvailableProblems())
+	assert.Equal(t, releasedAt, set.ProblemStartTime(2))
+	assert.Equal(t, releasedAt, set.ProblemStartTime(3))
+}
+
+func TestProblemSetClosed(t *testing.T) {
+	start := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	closesAt := start.Add(5 * 24 * time.Hour)
+
+	set := NewProblemSetWithSchedule(make([]Problem, 2), &ProblemReleaseSchedule{
+		StartReleaseAt: start,
+		ReleaseEvery:   24 * time.Hour,
+		ClosesAt:       closesAt,
+	})
+
+	set.now = func() time.Time { return closesAt.Add(-time.Second) }
+	assert.False(t, set.IsClosed())
+
+	set.now = func() time.Time { return closesAt }
+	assert.True(t, set.IsClosed())
+	assert.Equal(t, 2, set.AvailableProblems())
+}
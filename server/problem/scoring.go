@@ -1,31 +1,11 @@
 package problem
 
 import (
+	"fmt"
 	"math"
 	"time"
 )
 
-// CalculateCooldownEnd calculates the end of the cooldown period for a problem
-// given the total number of attempts, the time of the last submission and the
-// current time. If there is no cooldown, the returned timestamp is before the
-// current time, which may or may not be zero.
-func CalculateCooldownEnd(totalAttempts int, lastSubmitted, now time.Time) time.Time {
-	const cooldownThreshold = 2
-	const cooldownMultiplier = 2
-	const cooldownMax = 5 * time.Minute
-	const cooldown = 30 * time.Second
-
-	if totalAttempts < cooldownThreshold {
-		return time.Time{}
-	}
-
-	n := totalAttempts - cooldownThreshold + 1
-
-	return lastSubmitted.Add(min(
-		cooldown*time.Duration(cooldownMultiplier*n),
-		cooldownMax))
-}
-
 // PointsPerPart is the number of points awarded for solving a part of a
 // problem.
 const PointsPerPart = 100
@@ -44,6 +24,20 @@ func ScalePoints(t, startedAt time.Time, maxPoints float64, version ScoringVersi
 	return version.fn()(t, startedAt) * maxPoints
 }
 
+// ScalePoints scales the points for a part of the problem based on the time
+// the problem was started and the time the part was solved, using the
+// problem's scoring curve or scoring version.
+func (c ProblemConfig) ScalePoints(t, startedAt time.Time) float64 {
+	if c.ScoringCurve != nil {
+		maxPoints := c.PointsPerPart
+		if maxPoints == 0 {
+			maxPoints = PointsPerPart
+		}
+		return c.ScoringCurve.scale(t, startedAt) * maxPoints
+	}
+	return ScalePoints(t, startedAt, c.PointsPerPart, c.ScoringVersion)
+}
+
 // ScoringVersion is the version of the scoring function.
 type ScoringVersion int
 
@@ -51,16 +45,28 @@ const (
 	_ ScoringVersion = iota
 	V1ScoreScaling
 	V2ScoreScaling
+	// V3SolveCountScaling scales points by the number of teams that solved
+	// the part instead of by time. See [SolveCountPoints].
+	V3SolveCountScaling
 
-	maxScoreScalingVersion // latest = maxScoreScalingVersion - 1
+	maxScoreScalingVersion
 )
 
-const latestScoreScalingVersion = maxScoreScalingVersion - 1
+// latestScoreScalingVersion is the default scoring version. Solve count
+// scaling is opt-in, since it changes awarded points retroactively.
+const latestScoreScalingVersion = V2ScoreScaling
 
 func (v ScoringVersion) IsValid() bool {
 	return 0 < v && v < maxScoreScalingVersion
 }
 
+// IsSolveCountScaling returns true if the points depend on the number of
+// teams that solved the part rather than on the solve time. Points awarded
+// with such a version must be recomputed whenever a new solve lands.
+func (v ScoringVersion) IsSolveCountScaling() bool {
+	return v == V3SolveCountScaling
+}
+
 func (v ScoringVersion) fn() scoringFn {
 	switch v {
 	case 0:
@@ -69,6 +75,9 @@ func (v ScoringVersion) fn() scoringFn {
 		return scoreScalingV1
 	case V2ScoreScaling:
 		return scoreScalingV2
+	case V3SolveCountScaling:
+		// Solving later doesn't matter, only the number of solves does.
+		return func(t, startedAt time.Time) float64 { return 1 }
 	default:
 		panic("invalid scoring version")
 	}
@@ -104,6 +113,88 @@ func scoreScalingV2(t, startedAt time.Time) float64 {
 	return g(x)
 }
 
+// SolveCountPoints returns the points that every solver of a part gets once
+// the part has been solved by the given number of teams. The first solver gets
+// maxPoints, and the points decay quadratically down to a quarter of maxPoints
+// at solveCountDecay solves.
+//
+// If maxPoints is 0, it is set to PointsPerPart.
+func SolveCountPoints(solves int, maxPoints float64) float64 {
+	const minFraction = 0.25
+	const solveCountDecay = 16
+
+	if maxPoints == 0 {
+		maxPoints = PointsPerPart
+	}
+
+	x := float64(max(solves, 1)-1) / (solveCountDecay - 1)
+	f := 1 - (1-minFraction)*x*x
+	return clamp(f, minFraction, 1) * maxPoints
+}
+
 func clamp(x, minX, maxX float64) float64 {
 	return math.Max(minX, math.Min(maxX, x))
 }
+
+// WrongAnswerPenalty is an ICPC-style penalty for the incorrect attempts that
+// a team made before solving a part.
+type WrongAnswerPenalty struct {
+	// Points is the number of points deducted for each incorrect attempt.
+	Points float64 `json:"points,omitempty"`
+	// Minutes is the number of minutes added to the solve time for each
+	// incorrect attempt. It only affects time scaled scoring.
+	Minutes float64 `json:"minutes,omitempty"`
+}
+
+// IsZero returns true if the penalty doesn't penalize anything.
+func (p WrongAnswerPenalty) IsZero() bool {
+	return p.Points == 0 && p.Minutes == 0
+}
+
+func (p WrongAnswerPenalty) validate(version ScoringVersion) error {
+	if p.Points < 0 || p.Minutes < 0 {
+		return fmt.Errorf("penalty must not be negative")
+	}
+	if p.Minutes > 0 && version.IsSolveCountScaling() {
+		return fmt.Errorf("penalty minutes cannot be used with solve count scaling")
+	}
+	return nil
+}
+
+// Delay returns the time added to the solve time for the given number of
+// incorrect attempts.
+func (p WrongAnswerPenalty) Delay(attempts int) time.Duration {
+	return time.Duration(float64(attempts) * p.Minutes * float64(time.Minute))
+}
+
+// Apply returns the points left after deducting the penalty points for the
+// given number of incorrect attempts. The result is never below 0, so a
+// solve can never cost the team points.
+func (p WrongAnswerPenalty) Apply(points float64, attempts int) float64 {
+	return max(0, points-float64(attempts)*p.Points)
+}
+
+// DefaultDecayStartCap is the default of [ProblemConfig.DecayStartCapHours].
+const DefaultDecayStartCap = 24 * time.Hour
+
+// DecayStart returns the time that the score decay of a team starts at, given
+// the release time of the problem and the time that the team first opened it.
+// The first open time may be zero if the team never opened the problem.
+func (c ProblemConfig) DecayStart(releasedAt, firstOpenedAt time.Time) time.Time {
+	if !c.DecayFromFirstOpen || firstOpenedAt.IsZero() {
+		return releasedAt
+	}
+
+	maxDelay := DefaultDecayStartCap
+	if c.DecayStartCapHours > 0 {
+		maxDelay = time.Duration(c.DecayStartCapHours * float64(time.Hour))
+	}
+
+	if firstOpenedAt.Before(releasedAt) {
+		return releasedAt
+	}
+	if latest := releasedAt.Add(maxDelay); firstOpenedAt.After(latest) {
+		return latest
+	}
+	return firstOpenedAt
+}
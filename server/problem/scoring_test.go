@@ -0,0 +1,60 @@
+package problem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSolveCountPoints(t *testing.T) {
+	tests := []struct {
+		solves int
+		points float64
+	}{
+		{0, 100},
+		{1, 100},
+		{6, 91.66666666666666},
+		{16, 25},
+		{100, 25},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.points, SolveCountPoints(test.solves, 100), "solves = %d", test.solves)
+	}
+
+	for solves := 1; solves < 20; solves++ {
+		assert.True(t,
+			SolveCountPoints(solves+1, 100) <= SolveCountPoints(solves, 100),
+			"points must not increase with more solves")
+	}
+}
+
+func TestWrongAnswerPenalty(t *testing.T) {
+	penalty := WrongAnswerPenalty{Points: 10, Minutes: 20}
+
+	assert.Equal(t, 100.0, penalty.Apply(100, 0))
+	assert.Equal(t, 70.0, penalty.Apply(100, 3))
+	assert.Equal(t, 0.0, penalty.Apply(100, 20), "penalty must not go below 0")
+	assert.Equal(t, time.Hour, penalty.Delay(3))
+
+	assert.NoError(t, penalty.validate(V2ScoreScaling))
+	assert.Error(t, penalty.validate(V3SolveCountScaling))
+	assert.NoError(t, WrongAnswerPenalty{Points: 10}.validate(V3SolveCountScaling))
+	assert.Error(t, WrongAnswerPenalty{Points: -1}.validate(V2ScoreScaling))
+}
+
+func TestDecayStart(t *testing.T) {
+	release := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	opened := release.Add(5 * time.Hour)
+
+	assert.Equal(t, release, ProblemConfig{}.DecayStart(release, opened))
+
+	cfg := ProblemConfig{DecayFromFirstOpen: true}
+	assert.Equal(t, opened, cfg.DecayStart(release, opened))
+	assert.Equal(t, release, cfg.DecayStart(release, time.Time{}))
+	assert.Equal(t, release.Add(DefaultDecayStartCap), cfg.DecayStart(release, release.Add(48*time.Hour)))
+
+	cfg.DecayStartCapHours = 2
+	assert.Equal(t, release.Add(2*time.Hour), cfg.DecayStart(release, opened))
+}
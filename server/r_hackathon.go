@@ -5,7 +5,6 @@ import (
 	"net/http"
 	"slices"
 	"strings"
-	"time"
 
 	"dev.acmcsuf.com/march-madness-2024/internal/config"
 	"dev.acmcsuf.com/march-madness-2024/server/db"
@@ -51,7 +50,7 @@ func (s *Server) hackathonPage(w http.ResponseWriter, r *http.Request) {
 			Username: u.Username,
 			TeamName: u.TeamName,
 		},
-		HackathonConfig: s.config.HackathonConfig,
+		HackathonConfig: s.live.Load().HackathonConfig,
 		Submission:      form,
 	})
 }
@@ -64,7 +63,7 @@ var categories = []string{
 }
 
 func (s *Server) submitHackathon(w http.ResponseWriter, r *http.Request) {
-	if !s.config.HackathonConfig.IsOpen(time.Now()) {
+	if !s.live.Load().HackathonConfig.IsOpen(s.clock.Now()) {
 		http.Error(w, "hackathon is not open", http.StatusBadRequest)
 		return
 	}
@@ -99,7 +98,8 @@ func (s *Server) submitHackathon(w http.ResponseWriter, r *http.Request) {
 			String: submission.ProjectDescription,
 			Valid:  submission.ProjectDescription != "",
 		},
-		Category: submission.Category,
+		Category:    submission.Category,
+		SubmittedAt: db.NewDateTime(s.clock.Now()),
 	}); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
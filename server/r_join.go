@@ -20,9 +20,11 @@ func (s *Server) routeJoin(r chi.Router) {
 type joinPageData struct {
 	frontend.ComponentContext
 	OpenRegistrationTime time.Time
+	Divisions            []Division
 	FillingUsername      string
 	FillingTeamName      string
 	FillingTeamCode      string
+	FillingDivision      string
 	Error                string
 }
 
@@ -33,7 +35,9 @@ func (s *Server) joinPage(w http.ResponseWriter, r *http.Request) {
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		OpenRegistrationTime: s.config.OpenRegistrationTime,
+		OpenRegistrationTime: s.live.Load().OpenRegistrationTime,
+		Divisions:            s.Divisions(),
+		FillingDivision:      s.defaultDivision().ID,
 	})
 }
 
@@ -53,7 +57,8 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		OpenRegistrationTime: s.config.OpenRegistrationTime,
+		OpenRegistrationTime: s.live.Load().OpenRegistrationTime,
+		Divisions:            s.Divisions(),
 	}
 
 	writeError := func(err error) {
@@ -66,6 +71,7 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 		Username string `schema:"username"`
 		TeamName string `schema:"team_name"`
 		TeamCode string `schema:"team_code"`
+		Division string `schema:"division"`
 	}
 	if err := unmarshalForm(r, &data); err != nil {
 		writeError(err)
@@ -75,6 +81,7 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 	pageData.FillingUsername = data.Username
 	pageData.FillingTeamName = data.TeamName
 	pageData.FillingTeamCode = data.TeamCode
+	pageData.FillingDivision = data.Division
 
 	if data.TeamName != "" && data.TeamCode != "" {
 		writeError(fmt.Errorf("cannot provide both team name and team code"))
@@ -115,6 +122,16 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The division only matters when creating a new team. Teams joined using
+	// a team code keep their own division.
+	if len(s.Divisions()) == 1 {
+		data.Division = s.defaultDivision().ID
+	}
+	if data.TeamCode == "" && s.division(data.Division) == nil {
+		writeError(fmt.Errorf("invalid division"))
+		return
+	}
+
 	err := s.database.Tx(func(q *db.Queries) (err error) {
 		var isLeader bool
 		if isAuthenticated {
@@ -148,6 +165,8 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 			_, err := q.CreateTeam(ctx, db.CreateTeamParams{
 				TeamName:   data.TeamName,
 				InviteCode: generateInviteCode(),
+				Division:   data.Division,
+				CreatedAt:  db.NewDateTime(s.clock.Now()),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create team: %w", err)
@@ -159,6 +178,7 @@ func (s *Server) join(w http.ResponseWriter, r *http.Request) {
 			TeamName: data.TeamName,
 			Username: data.Username,
 			IsLeader: isLeader,
+			JoinedAt: db.NewDateTime(s.clock.Now()),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to join team: %w", err)
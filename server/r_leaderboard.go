@@ -4,22 +4,40 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
 	"dev.acmcsuf.com/march-madness-2024/server/frontend"
 	"github.com/go-chi/chi/v5"
 )
 
 func (s *Server) routeLeaderboard(r chi.Router) {
 	r.Get("/", s.leaderboard)
+	r.Get("/individual", s.individualLeaderboard)
 }
 
 type leaderboardPageData struct {
 	frontend.ComponentContext
+	Division  *Division
+	Divisions []Division
 	StartedAt time.Time
 	Table     leaderboardTeamPointsTable
 	Events    []leaderboardTeamPointsEvent
+	HasEnded  bool
+	// FirstSolves lists the first solvers of each part, ordered by day and
+	// part.
+	FirstSolves []leaderboardFirstSolves
+	// Freeze describes the freeze of the leaderboard, if it is frozen.
+	Freeze leaderboardFreeze
+}
+
+type leaderboardFirstSolves struct {
+	Day    problemDay
+	Part   int
+	Solves []db.FirstSolve
 }
 
 // TODO: this is awful, refactor it maybe
@@ -29,6 +47,26 @@ type leaderboardTeamPointsTable struct {
 	TeamMembers      [][]string
 	TeamPoints       [][]teamPoints
 	WeekOfCodeSolves [][]int8 // list of teams, each containing N days
+	Revealed         []bool   // whether the team was revealed after a freeze
+	Standings        []TeamStanding
+	Tiebreakers      []config.Tiebreaker
+}
+
+// TiebreakTooltip describes the tiebreak values of the team.
+func (t leaderboardTeamPointsTable) TiebreakTooltip(teamIx int) string {
+	vals := make([]string, len(t.Tiebreakers))
+	for i, tiebreaker := range t.Tiebreakers {
+		vals[i] = t.Standings[teamIx].TiebreakValue(tiebreaker)
+	}
+	return strings.Join(vals, ", ")
+}
+
+// IsTied returns true if the team has the same points as the team ranked
+// right above or below it, so that its tiebreak values matter.
+func (t leaderboardTeamPointsTable) IsTied(teamIx int) bool {
+	points := displayedPoints(t.Standings[teamIx].Points)
+	return (teamIx > 0 && displayedPoints(t.Standings[teamIx-1].Points) == points) ||
+		(teamIx+1 < len(t.Standings) && displayedPoints(t.Standings[teamIx+1].Points) == points)
 }
 
 type teamPoints struct {
@@ -58,51 +96,87 @@ func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
 	u := getAuthentication(r)
 	ctx := r.Context()
 
+	division, err := s.requestDivision(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
 	var table leaderboardTeamPointsTable
 
 	/*
-	 * Scan for team names and team totals
+	 * Scan for the standings and their breakdown
 	 */
 
-	totals, err := s.database.TeamPointsTotal(ctx)
+	data, err := loadStandingsData(ctx, s.database.Queries)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "failed to scan team points total", "err", err)
+		s.logger.ErrorContext(ctx, "failed to load standings", "err", err)
 
 		writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	teamIndices := make(map[string]int, len(totals))
-	table.Teams = make([]string, len(totals))
-	table.TeamTotals = make([]float64, len(totals))
-	for i, row := range totals {
-		teamIndices[row.TeamName] = i
-		table.Teams[i] = row.TeamName
-		table.TeamTotals[i] = row.Points.Float64
-	}
-
-	/*
-	 * Scan for team points breakdown
-	 */
-
-	points, err := s.database.TeamPointsEach(ctx)
+	freeze, err := s.leaderboardFreeze(ctx, division, data, u.TeamName)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "failed to scan team points each", "err", err)
+		s.logger.ErrorContext(ctx, "failed to get leaderboard freeze", "err", err)
 
 		writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	table.TeamPoints = make([][]teamPoints, len(table.Teams))
-	for _, row := range points {
-		ti, ok := teamIndices[row.TeamName]
-		if !ok {
+	inDivision := make(map[string]bool, len(data.teams))
+	for _, team := range data.teams {
+		if team.Division == division.ID {
+			inDivision[team.TeamName] = true
+		}
+	}
+
+	breakdowns := make(map[string][]teamPoints)
+	for _, entry := range data.points {
+		if !inDivision[entry.TeamName] || !freeze.isVisible(entry.TeamName, entry.AddedAt.Time()) {
+			continue
+		}
+
+		// Points awarded by admins are broken down by their reason, all other
+		// sources are summed up.
+		reason := PointsSource(entry.Source).Label()
+		if PointsSource(entry.Source) == PointsFromAdmin {
+			reason = entry.Reason
+		}
+
+		breakdown := breakdowns[entry.TeamName]
+		i := slices.IndexFunc(breakdown, func(p teamPoints) bool { return p.Reason == reason })
+		if i == -1 {
+			i = len(breakdown)
+			breakdown = append(breakdown, teamPoints{Reason: reason})
+		}
+		breakdown[i].Points += entry.Points
+		breakdowns[entry.TeamName] = breakdown
+	}
+
+	standings := data.standings(division.ID, freeze.isVisible)
+	table.Tiebreakers = s.live.Load().Leaderboard.TiebreakRules()
+	RankStandings(standings, table.Tiebreakers)
+
+	teamIndices := make(map[string]int, len(standings))
+	for _, standing := range standings {
+		// Teams are listed once they have any points, even if all of them are
+		// hidden.
+		if !standing.hasPoints {
 			continue
 		}
-		table.TeamPoints[ti] = append(table.TeamPoints[ti], teamPoints{
-			Reason: row.Reason,
-			Points: row.Points.Float64,
+
+		breakdown := breakdowns[standing.TeamName]
+		slices.SortFunc(breakdown, func(a, b teamPoints) int {
+			return strings.Compare(a.Reason, b.Reason)
 		})
+
+		teamIndices[standing.TeamName] = len(table.Teams)
+		table.Teams = append(table.Teams, standing.TeamName)
+		table.TeamTotals = append(table.TeamTotals, standing.Points)
+		table.TeamPoints = append(table.TeamPoints, breakdown)
+		table.Standings = append(table.Standings, standing)
+		table.Revealed = append(table.Revealed, freeze.Revealed[standing.TeamName])
 	}
 
 	/*
@@ -130,26 +204,22 @@ func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
 	 * Scan for week of code solves
 	 */
 
-	weekOfCodeSolves, err := s.database.ListAllCorrectSubmissions(ctx)
-	if err != nil {
-		s.logger.ErrorContext(ctx, "failed to scan week of code solves", "err", err)
-
-		writeError(w, http.StatusInternalServerError, err)
-		return
-	}
-
 	table.WeekOfCodeSolves = make([][]int8, len(table.Teams))
 	for i := range table.WeekOfCodeSolves {
-		table.WeekOfCodeSolves[i] = make([]int8, s.config.Problems.TotalProblems())
+		table.WeekOfCodeSolves[i] = make([]int8, division.Problems.TotalProblems())
 	}
-	for _, row := range weekOfCodeSolves {
-		day, part2, ok := s.parseProblemID(row.ProblemID)
+	for _, row := range data.submissions {
+		if !row.Correct {
+			continue
+		}
+
+		day, part2, ok := division.parseProblemID(row.ProblemID)
 		if !ok {
 			continue
 		}
 
 		ti, ok := teamIndices[row.TeamName]
-		if !ok {
+		if !ok || !freeze.isVisible(row.TeamName, row.SubmittedAt.Time()) {
 			continue
 		}
 
@@ -170,6 +240,9 @@ func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
 
 	events := make([]leaderboardTeamPointsEvent, 0, len(rows))
 	for _, row := range rows {
+		if !inDivision[row.TeamName] || !freeze.isVisible(row.TeamName, row.AddedAt.Time()) {
+			continue
+		}
 		events = append(events, leaderboardTeamPointsEvent{
 			TeamName: row.TeamName,
 			AddedAt:  row.AddedAt.Time(),
@@ -177,34 +250,120 @@ func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	/*
+	 * Scan for first solves
+	 */
+
+	firstSolveRows, err := s.database.ListFirstSolves(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to scan first solves", "err", err)
+
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var firstSolves []leaderboardFirstSolves
+	for _, row := range firstSolveRows {
+		if row.Division != division.ID || !freeze.isVisible(row.TeamName, row.SolvedAt.Time()) {
+			continue
+		}
+
+		day, part2, ok := division.parseProblemID(row.ProblemID)
+		if !ok {
+			continue
+		}
+
+		part := 1
+		if part2 {
+			part = 2
+		}
+
+		i := slices.IndexFunc(firstSolves, func(f leaderboardFirstSolves) bool {
+			return f.Day == day && f.Part == part
+		})
+		if i == -1 {
+			i = len(firstSolves)
+			firstSolves = append(firstSolves, leaderboardFirstSolves{Day: day, Part: part})
+		}
+		firstSolves[i].Solves = append(firstSolves[i].Solves, row)
+	}
+
+	slices.SortFunc(firstSolves, func(a, b leaderboardFirstSolves) int {
+		if a.Day != b.Day {
+			return int(a.Day - b.Day)
+		}
+		return a.Part - b.Part
+	})
+
 	s.renderTemplate(w, "leaderboard", leaderboardPageData{
 		ComponentContext: frontend.ComponentContext{
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		StartedAt: s.problems.StartedAt(),
+		Division:  division,
+		Divisions: s.Divisions(),
+		StartedAt: division.Problems.StartedAt(),
 		Table:     table,
 		Events:    events,
+		HasEnded:  division.Problems.IsClosed(),
+		Freeze:    freeze,
+
+		FirstSolves: firstSolves,
 	})
 }
 
-func (s *Server) parseProblemID(id string) (day problemDay, part2 bool, ok bool) {
-	switch {
-	case strings.HasSuffix(id, "/part1"):
-		part2 = false
-		id = strings.TrimSuffix(id, "/part1")
-	case strings.HasSuffix(id, "/part2"):
-		part2 = true
-		id = strings.TrimSuffix(id, "/part2")
-	default:
+type individualLeaderboardPageData struct {
+	frontend.ComponentContext
+	Division  *Division
+	Divisions []Division
+	Users     []UserStanding
+	HasEnded  bool
+	Freeze    leaderboardFreeze
+}
+
+func (s *Server) individualLeaderboard(w http.ResponseWriter, r *http.Request) {
+	u := getAuthentication(r)
+	ctx := r.Context()
+
+	division, err := s.requestDivision(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
 		return
 	}
-	for i, problem := range s.config.Problems.Problems() {
-		if problem.ID == id {
-			day = problemDay(i + 1)
-			ok = true
-			return
-		}
+
+	data, err := loadStandingsData(ctx, s.database.Queries)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load standings", "err", err)
+
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	voided, err := s.database.ListVoidedProblems(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to scan voided problems", "err", err)
+
+		writeError(w, http.StatusInternalServerError, err)
+		return
 	}
-	return
+
+	freeze, err := s.leaderboardFreeze(ctx, division, data, u.TeamName)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get leaderboard freeze", "err", err)
+
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.renderTemplate(w, "leaderboard_individual", individualLeaderboardPageData{
+		ComponentContext: frontend.ComponentContext{
+			TeamName: u.TeamName,
+			Username: u.Username,
+		},
+		Division:  division,
+		Divisions: s.Divisions(),
+		Users:     data.userStandings(division.ID, voided, freeze.isVisible),
+		HasEnded:  division.Problems.IsClosed(),
+		Freeze:    freeze,
+	})
 }
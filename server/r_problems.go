@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -28,64 +31,234 @@ func (s *Server) routeProblems(r chi.Router) {
 type problemsPageData struct {
 	frontend.ComponentContext
 	Problems      *problem.ProblemSet
+	Division      *Division
 	PointsPerPart float64
+	IsClosed      bool
 }
 
 func (s *Server) listProblems(w http.ResponseWriter, r *http.Request) {
 	u := getAuthentication(r)
+
+	division, err := s.requestDivision(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
 	s.renderTemplate(w, "problems", problemsPageData{
 		ComponentContext: frontend.ComponentContext{
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		Problems:      s.problems,
+		Problems:      division.Problems,
+		Division:      division,
 		PointsPerPart: problem.PointsPerPart,
+		IsClosed:      division.Problems.IsClosed(),
 	})
 }
 
 type problemPageData struct {
 	frontend.ComponentContext
 	Problem       *problem.Problem
+	Division      *Division
+	InDivision    bool
 	Day           problemDay
 	PointsPerPart float64
 	PPPIsDefault  bool
 	SolvedPart1   bool
 	SolvedPart2   bool
+	IsClosed      bool
+	// VoidedParts lists the voided parts of the problem, if any.
+	VoidedParts []voidedPart
+	// PartPoints is the current worth of each part if the problem uses solve
+	// count scaling, or nil otherwise.
+	PartPoints []float64
+	// FirstSolves lists the first solvers of each part.
+	FirstSolves [2][]db.FirstSolve
+	// Cooldown is the remaining cooldown before the next part can be
+	// submitted, or 0 if there is none.
+	Cooldown     time.Duration
+	CooldownTime time.Time
+	// DecayStartedAt is when the team's score decay started if the problem
+	// measures it from the team's first open, or zero otherwise.
+	DecayStartedAt time.Time
+	// Submissions is the team's submission history for the problem, oldest
+	// first.
+	Submissions []problemSubmission
+}
+
+type problemSubmission struct {
+	Part int
+	db.TeamSubmitAttempt
+}
+
+type voidedPart struct {
+	Part int
+	db.VoidedProblem
 }
 
 func (s *Server) viewProblem(w http.ResponseWriter, r *http.Request) {
 	u := getAuthentication(r)
 	ctx := r.Context()
 
-	p, day, err := s.getProblemFromRequest(r)
+	p, division, day, err := s.getProblemFromRequest(r)
 	if err != nil {
 		writeError(w, http.StatusNotFound, err)
 		return
 	}
 
+	inDivision := s.teamDivision(r) == division
+
+	var decayStartedAt time.Time
+	if u.TeamName != "" && inDivision {
+		s.startProblem(ctx, p, u)
+
+		if p.DecayFromFirstOpen {
+			decayStartedAt, err = decayStart(ctx, s.database.Queries, division, p, day, u.TeamName)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+	}
+
+	voided, err := s.voidedProblems(ctx, division)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var voidedParts []voidedPart
+	for part := 1; part <= 2; part++ {
+		if v, ok := voided[division.problemID(day, part == 2)]; ok {
+			voidedParts = append(voidedParts, voidedPart{part, v})
+		}
+	}
+
+	// While the leaderboard is frozen, the solve counts and first solves must
+	// not give away the solves that it hides.
+	var data standingsData
+	var freeze leaderboardFreeze
+	if s.live.Load().Leaderboard.IsFrozen(s.clock.Now()) {
+		data, err = loadStandingsData(ctx, s.database.Queries)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		freeze, err = s.leaderboardFreeze(ctx, division, data, u.TeamName)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	var partPoints []float64
+	if p.ScoringVersion.IsSolveCountScaling() {
+		for part := 1; part <= 2; part++ {
+			problemID := division.problemID(day, part == 2)
+
+			var solves int64
+			if freeze.IsFrozen() {
+				solves = int64(data.countSolves(division.ID, problemID, freeze.isVisible))
+			} else {
+				solves, err = s.database.CountSolves(ctx, db.CountSolvesParams{
+					ProblemID: problemID,
+					Division:  division.ID,
+				})
+				if err != nil {
+					writeError(w, http.StatusInternalServerError, err)
+					return
+				}
+			}
+			// The next solver would be solver number solves+1.
+			partPoints = append(partPoints, problem.SolveCountPoints(int(solves)+1, p.PointsPerPart))
+		}
+	}
+
+	firstSolves, err := s.database.ListFirstSolves(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var problemFirstSolves [2][]db.FirstSolve
+	for _, solve := range firstSolves {
+		if solve.Division != division.ID || !freeze.isVisible(solve.TeamName, solve.SolvedAt.Time()) {
+			continue
+		}
+		switch solve.ProblemID {
+		case division.problemID(day, false):
+			problemFirstSolves[0] = append(problemFirstSolves[0], solve)
+		case division.problemID(day, true):
+			problemFirstSolves[1] = append(problemFirstSolves[1], solve)
+		}
+	}
+
 	var p1solves, p2solves int64
-	if u.TeamName != "" {
+	if u.TeamName != "" && inDivision {
 		p1solves, _ = s.database.HasSolved(ctx, db.HasSolvedParams{
 			TeamName:  u.TeamName,
-			ProblemID: s.problemID(day, false),
+			ProblemID: division.problemID(day, false),
 		})
 		p2solves, _ = s.database.HasSolved(ctx, db.HasSolvedParams{
 			TeamName:  u.TeamName,
-			ProblemID: s.problemID(day, true),
+			ProblemID: division.problemID(day, true),
 		})
 	}
 
+	var submissions []problemSubmission
+	if u.TeamName != "" && inDivision {
+		for part := 1; part <= 2; part++ {
+			partSubmissions, err := s.database.ListSubmissions(ctx, db.ListSubmissionsParams{
+				TeamName:  u.TeamName,
+				ProblemID: division.problemID(day, part == 2),
+			})
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			for _, submission := range partSubmissions {
+				submissions = append(submissions, problemSubmission{part, submission})
+			}
+		}
+		slices.SortStableFunc(submissions, func(a, b problemSubmission) int {
+			return a.SubmittedAt.Time().Compare(b.SubmittedAt.Time())
+		})
+	}
+
+	var cooldown time.Duration
+	var cooldownTime time.Time
+	if u.TeamName != "" && inDivision && p2solves == 0 {
+		cooldownTime, err = cooldownEnd(ctx, s.database.Queries, p, division, day, p1solves > 0, u)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		cooldown = max(0, cooldownTime.Sub(s.clock.Now()))
+	}
+
 	s.renderTemplate(w, "problem", problemPageData{
 		ComponentContext: frontend.ComponentContext{
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
 		Problem:       p,
+		Division:      division,
+		InDivision:    inDivision,
 		Day:           day,
 		PointsPerPart: p.PointsPerPart,
 		PPPIsDefault:  p.PointsPerPart == problem.PointsPerPart,
 		SolvedPart1:   p1solves > 0,
 		SolvedPart2:   p2solves > 0,
+		IsClosed:      division.Problems.IsClosed(),
+		VoidedParts:   voidedParts,
+		PartPoints:    partPoints,
+		FirstSolves:   problemFirstSolves,
+		Cooldown:      cooldown,
+		CooldownTime:  cooldownTime,
+
+		DecayStartedAt: decayStartedAt,
+		Submissions:    submissions,
 	})
 }
 
@@ -93,12 +266,19 @@ func (s *Server) viewProblemInput(w http.ResponseWriter, r *http.Request) {
 	u := getAuthentication(r)
 	ctx := r.Context()
 
-	p, _, err := s.getProblemFromRequest(r)
+	p, division, _, err := s.getProblemFromRequest(r)
 	if err != nil {
 		writeError(w, http.StatusNotFound, err)
 		return
 	}
 
+	if s.teamDivision(r) != division {
+		writeError(w, http.StatusForbidden, errNotInDivision)
+		return
+	}
+
+	s.startProblem(ctx, p, u)
+
 	input, err := p.Input(ctx, problem.StringToSeed(u.TeamName))
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
@@ -109,26 +289,64 @@ func (s *Server) viewProblemInput(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, input)
 }
 
+// startProblem records that the user's team opened the problem, unless it
+// already has. Failures are only logged, since they shouldn't keep the user
+// from the problem.
+func (s *Server) startProblem(ctx context.Context, p *problem.Problem, u authenticatedUser) {
+	err := s.database.StartProblem(ctx, db.StartProblemParams{
+		TeamName:  u.TeamName,
+		ProblemID: p.ID,
+		StartedAt: db.NewDateTime(s.clock.Now()),
+		StartedBy: u.Username,
+	})
+	if err != nil {
+		s.logger.WarnContext(ctx,
+			"failed to record problem start",
+			"team", u.TeamName,
+			"problem", p.ID,
+			"err", err)
+	}
+}
+
 type problemResultPageData struct {
 	frontend.ComponentContext
+	Division      *Division
 	Day           problemDay
 	Part          int
 	Cooldown      time.Duration
 	CooldownTime  time.Time
 	Correct       bool
+	Practice      bool
+	Voided        bool
 	PointsAwarded float64
+	// FirstSolveRank is the rank of the team among the first solvers if it
+	// got a first solve bonus, or 0 otherwise.
+	FirstSolveRank  int
+	FirstSolveBonus float64
+	// IncorrectAttempts is the number of incorrect attempts made before the
+	// solve, and Penalty is the points deducted for them.
+	IncorrectAttempts int
+	Penalty           float64
+	// Duplicate is true if the answer was rejected because the team already
+	// submitted it as a wrong answer. It isn't recorded or counted.
+	Duplicate bool
 }
 
 func (s *Server) submitProblem(w http.ResponseWriter, r *http.Request) {
 	u := getAuthentication(r)
 	ctx := r.Context()
 
-	p, day, err := s.getProblemFromRequest(r)
+	p, division, day, err := s.getProblemFromRequest(r)
 	if err != nil {
 		writeError(w, http.StatusNotFound, err)
 		return
 	}
 
+	if s.teamDivision(r) != division {
+		writeError(w, http.StatusForbidden, errNotInDivision)
+		return
+	}
+
 	var data struct {
 		Answer int64 `schema:"answer"`
 		Part   int   `schema:"part"`
@@ -143,55 +361,98 @@ func (s *Server) submitProblem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	problemID := s.problemID(day, data.Part == 2)
-	var numSolves int64
-	var numAttempts int64
-	var lastAttempt time.Time
+	problemID := division.problemID(day, data.Part == 2)
 
-	err = s.database.Tx(func(q *db.Queries) (err error) {
-		numSolves, err = s.database.HasSolved(ctx, db.HasSolvedParams{
+	voided, err := s.voidedProblems(ctx, division)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	_, isVoided := voided[problemID]
+
+	var duplicates int64
+	var cooldownTime time.Time
+
+	checkSolved := func(q *db.Queries) error {
+		solves, err := q.HasSolved(ctx, db.HasSolvedParams{
 			TeamName:  u.TeamName,
 			ProblemID: problemID,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to check if solved: %w", err)
 		}
-		if numSolves > 0 {
-			return fmt.Errorf("problem is already solved")
+		if solves > 0 {
+			return errAlreadySolved
 		}
+		return nil
+	}
 
-		numAttempts, err = s.database.CountIncorrectSubmissions(ctx, db.CountIncorrectSubmissionsParams{
+	// Resubmitting a known wrong answer is rejected without recording it, so
+	// that it doesn't cost the team anything.
+	countDuplicates := func(q *db.Queries) (err error) {
+		duplicates, err = q.HasSubmittedWrongAnswer(ctx, db.HasSubmittedWrongAnswerParams{
 			TeamName:  u.TeamName,
 			ProblemID: problemID,
+			Answer:    sql.NullInt64{Int64: data.Answer, Valid: true},
 		})
 		if err != nil {
-			return fmt.Errorf("failed to count incorrect submissions: %w", err)
+			return fmt.Errorf("failed to check for duplicate answer: %w", err)
 		}
+		return nil
+	}
 
-		if numAttempts > 0 {
-			t, err := s.database.LastSubmissionTime(ctx, db.LastSubmissionTimeParams{
-				TeamName:  u.TeamName,
-				ProblemID: problemID,
-			})
-			if err != nil {
-				return fmt.Errorf("failed to get last submission time: %w", err)
-			}
-			lastAttempt = t.Time()
+	err = s.database.Tx(func(q *db.Queries) (err error) {
+		if err := checkSolved(q); err != nil {
+			return err
+		}
+
+		// Duplicates are rejected right away, even during a cooldown.
+		if err := countDuplicates(q); err != nil || duplicates > 0 {
+			return err
+		}
+
+		cooldownTime, err = cooldownEnd(ctx, q, p, division, day, data.Part == 2, u)
+		if err != nil {
+			return fmt.Errorf("failed to get cooldown: %w", err)
 		}
 
 		return nil
 	})
+	if errors.Is(err, errAlreadySolved) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	now := time.Now()
-	cooldownTime := problem.CalculateCooldownEnd(int(numAttempts), lastAttempt, now)
+	renderDuplicate := func() {
+		s.renderTemplate(w, "problem_result", problemResultPageData{
+			ComponentContext: frontend.ComponentContext{
+				TeamName: u.TeamName,
+				Username: u.Username,
+			},
+			Division:  division,
+			Day:       day,
+			Part:      data.Part,
+			Duplicate: true,
+		})
+	}
+
+	if duplicates > 0 {
+		renderDuplicate()
+		return
+	}
 
+	now := s.clock.Now()
 	cooldown := max(0, cooldownTime.Sub(now))
 	var correct bool
-	var points float64
+	var awarded awardedPoints
+
+	// Submissions after the competition has ended are still checked, but
+	// they're only recorded as practice and award no points.
+	practice := division.Problems.IsClosed()
 
 	if cooldown == 0 {
 		seed := problem.StringToSeed(u.TeamName)
@@ -211,43 +472,74 @@ func (s *Server) submitProblem(w http.ResponseWriter, r *http.Request) {
 		}
 
 		correct = answer == data.Answer
-		if correct {
-			points = problem.ScalePoints(
-				now, s.problems.ProblemStartTime(day.index()),
-				p.PointsPerPart, p.ScoringVersion)
-		}
 
 		err = s.database.Tx(func(q *db.Queries) error {
-			_, err := s.database.RecordSubmission(ctx, db.RecordSubmissionParams{
+			// Another submission may have solved the part or recorded the
+			// same answer since they were checked, so both are checked again
+			// along with recording this one. Otherwise, concurrent correct
+			// submissions would each be awarded points.
+			if err := checkSolved(q); err != nil {
+				return err
+			}
+			if err := countDuplicates(q); err != nil || duplicates > 0 {
+				return err
+			}
+
+			submission, err := q.RecordSubmission(ctx, db.RecordSubmissionParams{
 				TeamName: u.TeamName,
 				SubmittedBy: sql.NullString{
 					String: u.Username,
 					Valid:  true,
 				},
-				ProblemID: problemID,
-				Correct:   correct,
+				ProblemID:   problemID,
+				Correct:     correct,
+				Practice:    practice,
+				SubmittedAt: db.NewDateTime(now),
+				Answer:      sql.NullInt64{Int64: data.Answer, Valid: true},
 			})
 			if err != nil {
 				return fmt.Errorf("failed to record submission: %w", err)
 			}
 
-			if correct {
-				_, err = s.database.AddPoints(ctx, db.AddPointsParams{
-					TeamName: u.TeamName,
-					Points:   points,
-					Reason:   "week of code",
+			if correct && !practice && !isVoided {
+				startedAt, err := decayStart(ctx, q, division, p, day, u.TeamName)
+				if err != nil {
+					return err
+				}
+
+				awarded, err = s.awardSolvePoints(ctx, q, solvePoints{
+					division:  division,
+					problem:   p,
+					day:       day,
+					problemID: problemID,
+					part:      data.Part,
+					teamName:  u.TeamName,
+
+					submissionID: submission.ID,
+					// Score the solve at its recorded time, so that
+					// rescoring it later gives the same points.
+					solvedAt:  submission.SubmittedAt.Time(),
+					startedAt: startedAt,
 				})
 				if err != nil {
-					return fmt.Errorf("failed to add points: %w", err)
+					return fmt.Errorf("failed to award points: %w", err)
 				}
 			}
 
 			return nil
 		})
+		if errors.Is(err, errAlreadySolved) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
 		if err != nil {
 			writeError(w, http.StatusInternalServerError, err)
 			return
 		}
+		if duplicates > 0 {
+			renderDuplicate()
+			return
+		}
 	}
 
 	s.renderTemplate(w, "problem_result", problemResultPageData{
@@ -255,12 +547,20 @@ func (s *Server) submitProblem(w http.ResponseWriter, r *http.Request) {
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		Day:           day,
-		Part:          data.Part,
-		Correct:       correct,
-		Cooldown:      cooldown,
-		CooldownTime:  cooldownTime,
-		PointsAwarded: points,
+		Division:        division,
+		Day:             day,
+		Part:            data.Part,
+		Correct:         correct,
+		Practice:        practice,
+		Voided:          isVoided,
+		Cooldown:        cooldown,
+		CooldownTime:    cooldownTime,
+		PointsAwarded:   awarded.Points,
+		FirstSolveRank:  awarded.FirstSolveRank,
+		FirstSolveBonus: awarded.FirstSolveBonus,
+
+		IncorrectAttempts: awarded.IncorrectAttempts,
+		Penalty:           awarded.Penalty,
 	})
 }
 
@@ -270,28 +570,26 @@ func (p problemDay) index() int {
 	return int(p) - 1
 }
 
-func (s *Server) getProblemFromRequest(r *http.Request) (*problem.Problem, problemDay, error) {
+var (
+	errNotInDivision = errors.New("your team is not in this division")
+	errAlreadySolved = errors.New("problem is already solved")
+)
+
+func (s *Server) getProblemFromRequest(r *http.Request) (*problem.Problem, *Division, problemDay, error) {
+	division, err := s.requestDivision(r)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
 	day, err := strconv.Atoi(chi.URLParam(r, "problemDay"))
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, 0, err
 	}
 
-	p := s.problems.Problem(day - 1)
+	p := division.Problems.Problem(day - 1)
 	if p == nil {
-		return nil, 0, fmt.Errorf("problem %d not found", day)
+		return nil, nil, 0, fmt.Errorf("problem %d not found", day)
 	}
 
-	return p, problemDay(day), nil
-}
-
-func (s *Server) problemID(day problemDay, part2 bool) string {
-	problem := s.config.Problems.Problem(day.index())
-	if problem == nil {
-		return ""
-	}
-	if part2 {
-		return problem.ID + "/part2"
-	} else {
-		return problem.ID + "/part1"
-	}
+	return p, division, problemDay(day), nil
 }
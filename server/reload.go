@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reload atomically replaces the reloadable part of the server configuration.
+// Requests that are already being served keep using the old configuration.
+// The schedule overrides are applied to the new divisions before they are
+// swapped in. If an error is returned, the old configuration is kept.
+func (s *Server) Reload(ctx context.Context, cfg ReloadableConfig) error {
+	if len(cfg.Divisions) == 0 {
+		return errors.New("no divisions configured")
+	}
+
+	if err := CheckProblemIDs(ctx, s.database, cfg.Divisions); err != nil {
+		return err
+	}
+
+	if err := s.loadScheduleOverrides(ctx, cfg.Divisions); err != nil {
+		return fmt.Errorf("failed to load schedule overrides: %w", err)
+	}
+
+	s.live.Store(&cfg)
+	s.logger.InfoContext(ctx,
+		"reloaded configuration",
+		"divisions", len(cfg.Divisions))
+
+	return nil
+}
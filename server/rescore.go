@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+)
+
+// PointsAdjustment is a correction to the points that a team was awarded for
+// a problem part.
+type PointsAdjustment struct {
+	TeamName  string
+	ProblemID string
+	Part      int
+	Source    PointsSource
+	// Current is the sum of the points currently awarded, and Expected is
+	// the sum that they should be.
+	Current  float64
+	Expected float64
+}
+
+// Delta returns the points that must be added to correct the award.
+func (a PointsAdjustment) Delta() float64 {
+	return a.Expected - a.Current
+}
+
+// Rescore recomputes the solve points and wrong answer penalties of every
+// team in the division from the recorded correct submissions, using the
+// current problem configuration and schedule. It returns the adjustments
+// needed to bring the awarded points in line, without modifying anything.
+// Voided parts and first solve bonuses are left alone.
+func Rescore(ctx context.Context, q *db.Queries, division *Division) ([]PointsAdjustment, error) {
+	voided, err := q.ListVoidedProblems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voided problems: %w", err)
+	}
+
+	isVoided := make(map[string]bool, len(voided))
+	for _, v := range voided {
+		if v.Division == division.ID {
+			isVoided[v.ProblemID] = true
+		}
+	}
+
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	inDivision := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		if team.Division == division.ID {
+			inDivision[team.TeamName] = true
+		}
+	}
+
+	correct, err := q.ListAllCorrectSubmissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list correct submissions: %w", err)
+	}
+
+	var adjustments []PointsAdjustment
+
+	problems := division.Problems.AllProblems()
+	for i := range problems {
+		for _, part := range []int{1, 2} {
+			day := problemDay(i + 1)
+			problemID := division.problemID(day, part == 2)
+			if isVoided[problemID] {
+				continue
+			}
+
+			var solves []solvePoints
+			for _, row := range correct {
+				if row.ProblemID != problemID || !inDivision[row.TeamName] {
+					continue
+				}
+
+				startedAt, err := decayStart(ctx, q, division, &problems[i], day, row.TeamName)
+				if err != nil {
+					return nil, err
+				}
+
+				solves = append(solves, solvePoints{
+					division:  division,
+					problem:   &problems[i],
+					day:       day,
+					problemID: problemID,
+					part:      part,
+					teamName:  row.TeamName,
+
+					submissionID: row.ID,
+					solvedAt:     row.SubmittedAt.Time(),
+					startedAt:    startedAt,
+				})
+			}
+
+			expected, err := expectedSolvePoints(ctx, q, solves)
+			if err != nil {
+				return nil, err
+			}
+
+			current, err := q.ProblemPointsByTeam(ctx, db.ProblemPointsByTeamParams{
+				ProblemID: sql.NullString{String: problemID, Valid: true},
+				Division:  division.ID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get awarded points for %q: %w", problemID, err)
+			}
+
+			for _, row := range current {
+				source := PointsSource(row.Source)
+				if source != PointsFromSolve && source != PointsFromPenalty {
+					continue
+				}
+				key := rescoreKey{row.TeamName, source}
+				adjustment, ok := expected[key]
+				if !ok {
+					adjustment = PointsAdjustment{TeamName: row.TeamName, Source: source}
+				}
+				adjustment.Current = row.Points.Float64
+				expected[key] = adjustment
+			}
+
+			for _, team := range teams {
+				for _, source := range []PointsSource{PointsFromSolve, PointsFromPenalty} {
+					adjustment, ok := expected[rescoreKey{team.TeamName, source}]
+					if !ok || math.Abs(adjustment.Delta()) < 1e-9 {
+						continue
+					}
+					adjustment.ProblemID = problemID
+					adjustment.Part = part
+					adjustments = append(adjustments, adjustment)
+				}
+			}
+		}
+	}
+
+	return adjustments, nil
+}
+
+type rescoreKey struct {
+	teamName string
+	source   PointsSource
+}
+
+// expectedSolvePoints returns the points that the solvers of a problem part
+// should have been awarded.
+func expectedSolvePoints(ctx context.Context, q *db.Queries, solves []solvePoints) (map[rescoreKey]PointsAdjustment, error) {
+	expected := make(map[rescoreKey]PointsAdjustment, len(solves))
+
+	for _, solve := range solves {
+		var points float64
+		if solve.problem.ScoringVersion.IsSolveCountScaling() {
+			points = problem.SolveCountPoints(len(solves), solve.problem.PointsPerPart)
+		} else {
+			points = timedPoints(solve, solve.solvedAt)
+		}
+
+		expected[rescoreKey{solve.teamName, PointsFromSolve}] = PointsAdjustment{
+			TeamName: solve.teamName,
+			Source:   PointsFromSolve,
+			Expected: points,
+		}
+
+		if solve.problem.WrongAnswerPenalty.IsZero() {
+			continue
+		}
+
+		submissions, err := q.ListSubmissions(ctx, db.ListSubmissionsParams{
+			TeamName:  solve.teamName,
+			ProblemID: solve.problemID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list submissions: %w", err)
+		}
+
+		var attempts int
+		for _, submission := range submissions {
+			if !submission.Correct && !submission.SubmittedAt.Time().After(solve.solvedAt) {
+				attempts++
+			}
+		}
+
+		if penalty := wrongAnswerPenalty(solve, points, attempts); penalty > 0 {
+			expected[rescoreKey{solve.teamName, PointsFromPenalty}] = PointsAdjustment{
+				TeamName: solve.teamName,
+				Source:   PointsFromPenalty,
+				Expected: -penalty,
+			}
+		}
+	}
+
+	return expected, nil
+}
+
+// ApplyPointsAdjustments adds a ledger entry for each adjustment at the given
+// time, made by the given admin. The original entries are kept, so that the
+// points history stays intact.
+func ApplyPointsAdjustments(ctx context.Context, q *db.Queries, adjustments []PointsAdjustment, t time.Time, actor string) error {
+	for _, adjustment := range adjustments {
+		err := addPoints(ctx, q, db.AddPointsParams{
+			TeamName:  adjustment.TeamName,
+			Points:    adjustment.Delta(),
+			Reason:    "rescore",
+			Source:    string(adjustment.Source),
+			ProblemID: sql.NullString{String: adjustment.ProblemID, Valid: true},
+			Part:      sql.NullInt64{Int64: int64(adjustment.Part), Valid: true},
+			Actor:     sql.NullString{String: actor, Valid: actor != ""},
+			AddedAt:   db.NewDateTime(t),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
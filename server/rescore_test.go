@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRescore(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	solvedAt := start.Add(6 * time.Hour)
+
+	database, err := db.NewInMemory()
+	assert.NoError(t, err)
+	defer database.Close()
+
+	newDivision := func(version problem.ScoringVersion) *Division {
+		p := problem.NewProblem("problem", problem.ProblemDescription{}, nil, problem.ProblemConfig{
+			ScoringVersion:     version,
+			WrongAnswerPenalty: problem.WrongAnswerPenalty{Points: 10},
+		})
+		schedule := &problem.ProblemReleaseSchedule{StartReleaseAt: start, ReleaseEvery: 24 * time.Hour}
+		return &Division{Problems: problem.NewProblemSetWithSchedule([]problem.Problem{p}, schedule)}
+	}
+
+	division := newDivision(problem.V1ScoreScaling)
+	s := &Server{}
+
+	err = database.Tx(func(q *db.Queries) error {
+		_, err := q.CreateTeam(ctx, db.CreateTeamParams{
+			TeamName:   "team",
+			InviteCode: "code",
+			CreatedAt:  db.NewDateTime(start),
+		})
+		assert.NoError(t, err)
+
+		var submission db.TeamSubmitAttempt
+		for _, correct := range []bool{false, true} {
+			submission, err = q.RecordSubmission(ctx, db.RecordSubmissionParams{
+				TeamName:    "team",
+				ProblemID:   "problem/part1",
+				Correct:     correct,
+				SubmittedAt: db.NewDateTime(solvedAt),
+			})
+			assert.NoError(t, err)
+		}
+
+		_, err = s.awardSolvePoints(ctx, q, solvePoints{
+			division:     division,
+			problem:      &division.Problems.AllProblems()[0],
+			day:          1,
+			problemID:    "problem/part1",
+			part:         1,
+			teamName:     "team",
+			submissionID: submission.ID,
+			solvedAt:     solvedAt,
+			startedAt:    start,
+		})
+		return err
+	})
+	assert.NoError(t, err)
+
+	adjustments, err := Rescore(ctx, database.Queries, division)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(adjustments), "unchanged scoring must not need adjustments")
+
+	// Switching to solve count scaling makes the only solver worth the full
+	// points. The penalty stays the same, so only the solve points change.
+	v1Points := division.Problems.AllProblems()[0].ScalePoints(solvedAt, start)
+	division = newDivision(problem.V3SolveCountScaling)
+
+	adjustments, err = Rescore(ctx, database.Queries, division)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(adjustments))
+	assert.Equal(t, "team", adjustments[0].TeamName)
+	assert.Equal(t, "problem/part1", adjustments[0].ProblemID)
+	assert.Equal(t, PointsFromSolve, adjustments[0].Source)
+	assert.True(t, math.Abs(adjustments[0].Current-v1Points) < 1e-9, "current %v, want %v", adjustments[0].Current, v1Points)
+	assert.Equal(t, problem.PointsPerPart, adjustments[0].Expected)
+
+	applyAndTotal := func() float64 {
+		adjustments, err := Rescore(ctx, database.Queries, division)
+		assert.NoError(t, err)
+		err = database.Tx(func(q *db.Queries) error {
+			return ApplyPointsAdjustments(ctx, q, adjustments, solvedAt.Add(time.Hour), "admin")
+		})
+		assert.NoError(t, err)
+
+		points, err := database.ListPoints(ctx)
+		assert.NoError(t, err)
+		var total float64
+		for _, entry := range points {
+			total += entry.Points
+		}
+		return total
+	}
+
+	total := applyAndTotal()
+	assert.True(t, math.Abs(total-(problem.PointsPerPart-10)) < 1e-9, "total %v after applying", total)
+	assert.Equal(t, total, applyAndTotal(), "applying again must be a no-op")
+
+	adjustments, err = Rescore(ctx, database.Queries, division)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(adjustments))
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+)
+
+// scheduleOverridesPollInterval is how often the server reloads the schedule
+// overrides from the database. Overrides are written by competitionctl, which
+// runs in a separate process.
+const scheduleOverridesPollInterval = 5 * time.Second
+
+// SyncScheduleOverrides loads the schedule overrides from the database into
+// the problem set. It then keeps reloading them in the background until ctx
+// is canceled.
+func (s *Server) SyncScheduleOverrides(ctx context.Context) error {
+	if err := s.loadScheduleOverrides(ctx, s.Divisions()); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(scheduleOverridesPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.loadScheduleOverrides(ctx, s.Divisions()); err != nil {
+					s.logger.ErrorContext(ctx,
+						"failed to reload schedule overrides",
+						"err", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *Server) loadScheduleOverrides(ctx context.Context, divisions []Division) error {
+	rows, err := s.database.ListScheduleOverrides(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+
+	overrides := make(map[string][]problem.ScheduleOverride, len(divisions))
+	for _, row := range rows {
+		overrides[row.Division] = append(overrides[row.Division], ConvertScheduleOverride(row))
+	}
+
+	for _, division := range divisions {
+		if err := division.Problems.SetScheduleOverrides(overrides[division.ID]); err != nil {
+			return fmt.Errorf("failed to apply schedule overrides to division %q: %w", division.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// ConvertScheduleOverride converts a schedule override database row into a
+// problem.ScheduleOverride.
+func ConvertScheduleOverride(row db.ScheduleOverride) problem.ScheduleOverride {
+	return problem.ScheduleOverride{
+		Action:  problem.ScheduleOverrideAction(row.Action),
+		At:      row.CreatedAt.Time(),
+		Problem: int(row.ProblemIndex.Int64),
+		Delay:   time.Duration(row.DelaySeconds.Int64) * time.Second,
+	}
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+)
+
+// solvePoints describes the points of a newly solved problem part.
+type solvePoints struct {
+	division  *Division
+	problem   *problem.Problem
+	day       problemDay
+	problemID string
+	part      int
+	teamName  string
+	// submissionID is the ID of the correct submission.
+	submissionID int64
+	solvedAt     time.Time
+	// startedAt is when the score decay of the team starts, see
+	// [decayStart].
+	startedAt time.Time
+}
+
+// awardedPoints is the points awarded for a solve.
+type awardedPoints struct {
+	Points float64
+	// FirstSolveRank is the rank of the team among the first solvers of the
+	// part, or 0 if the team did not get a first solve bonus.
+	FirstSolveRank  int
+	FirstSolveBonus float64
+	// IncorrectAttempts is the number of incorrect attempts that the team made
+	// before solving the part, and Penalty is the points deducted for them.
+	IncorrectAttempts int
+	Penalty           float64
+}
+
+// awardSolvePoints awards the team its points for solving a problem part and
+// returns them. It must be called after the solve is recorded, within the same
+// transaction.
+func (s *Server) awardSolvePoints(ctx context.Context, q *db.Queries, solve solvePoints) (awardedPoints, error) {
+	var awarded awardedPoints
+	var err error
+
+	if solve.problem.ScoringVersion.IsSolveCountScaling() {
+		awarded.Points, err = awardSolveCountPoints(ctx, q, solve)
+	} else {
+		awarded.Points, err = awardTimedPoints(ctx, q, solve)
+	}
+	if err != nil {
+		return awarded, err
+	}
+
+	awarded.IncorrectAttempts, awarded.Penalty, err = awardWrongAnswerPenalty(ctx, q, solve, awarded.Points)
+	if err != nil {
+		return awarded, err
+	}
+	awarded.Points -= awarded.Penalty
+
+	awarded.FirstSolveRank, awarded.FirstSolveBonus, err = awardFirstSolveBonus(ctx, q, solve)
+	if err != nil {
+		return awarded, err
+	}
+
+	return awarded, nil
+}
+
+func awardTimedPoints(ctx context.Context, q *db.Queries, solve solvePoints) (float64, error) {
+	points := timedPoints(solve, solve.solvedAt)
+	err := addSolvePoints(ctx, q, solve, solve.teamName, points, PointsFromSolve)
+	return points, err
+}
+
+// awardSolveCountPoints awards points using solve count scaling. Every earlier
+// solver of the part also gets an adjustment entry, so that all solvers end up
+// with the same points, and their wrong answer penalties are adjusted to
+// match. The adjustments are added at the time of the new solve, which makes
+// them show up in the points history.
+func awardSolveCountPoints(ctx context.Context, q *db.Queries, solve solvePoints) (float64, error) {
+	solves, err := q.CountSolves(ctx, db.CountSolvesParams{
+		ProblemID: solve.problemID,
+		Division:  solve.division.ID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count solves: %w", err)
+	}
+
+	points := problem.SolveCountPoints(int(solves), solve.problem.PointsPerPart)
+
+	awarded, err := q.ProblemPointsByTeam(ctx, db.ProblemPointsByTeamParams{
+		ProblemID: sql.NullString{String: solve.problemID, Valid: true},
+		Division:  solve.division.ID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get awarded points: %w", err)
+	}
+
+	penalties := make(map[string]float64)
+	for _, row := range awarded {
+		if PointsSource(row.Source) == PointsFromPenalty {
+			penalties[row.TeamName] = -row.Points.Float64
+		}
+	}
+
+	for _, row := range awarded {
+		if row.TeamName == solve.teamName || PointsSource(row.Source) != PointsFromSolve {
+			continue
+		}
+
+		adjustment := points - row.Points.Float64
+		if math.Abs(adjustment) < 1e-9 {
+			continue
+		}
+
+		err := addSolvePoints(ctx, q, solve, row.TeamName, adjustment, PointsFromSolve)
+		if err != nil {
+			return 0, err
+		}
+
+		err = adjustWrongAnswerPenalty(ctx, q, solve, row.TeamName, points, penalties[row.TeamName])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = addSolvePoints(ctx, q, solve, solve.teamName, points, PointsFromSolve)
+	return points, err
+}
+
+// awardFirstSolveBonus awards the first solve bonus if the team is among the
+// first solvers of the part. Ranks are given in the order that solves are
+// committed, so ties within the same second are broken by whoever was first
+// to be recorded. If two solves race for the same rank, the later one takes
+// the next rank instead.
+func awardFirstSolveBonus(ctx context.Context, q *db.Queries, solve solvePoints) (rank int, bonus float64, err error) {
+	bonuses := solve.problem.FirstSolveBonuses
+	if len(bonuses) == 0 {
+		return 0, 0, nil
+	}
+
+	n, err := q.CountFirstSolves(ctx, db.CountFirstSolvesParams{
+		Division:  solve.division.ID,
+		ProblemID: solve.problemID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count first solves: %w", err)
+	}
+	if int(n) >= len(bonuses) {
+		return 0, 0, nil
+	}
+
+	for rank = int(n) + 1; rank <= len(bonuses); rank++ {
+		bonus = bonuses[rank-1]
+
+		_, err = q.AddFirstSolve(ctx, db.AddFirstSolveParams{
+			Division:  solve.division.ID,
+			ProblemID: solve.problemID,
+			Rank:      int64(rank),
+			TeamName:  solve.teamName,
+			SolvedAt:  db.NewDateTime(solve.solvedAt),
+			Bonus:     bonus,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// A concurrent solve took the rank first.
+			continue
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to record first solve: %w", err)
+		}
+
+		err = addSolvePoints(ctx, q, solve, solve.teamName, bonus, PointsFromFirstSolve)
+		return rank, bonus, err
+	}
+
+	return 0, 0, nil
+}
+
+// awardWrongAnswerPenalty deducts the wrong answer penalty of the problem
+// from the points of a solve. The penalty is recorded as its own entry so that
+// it shows up separately in the points breakdown. Penalty minutes are applied
+// by scoring the solve as if it happened later.
+func awardWrongAnswerPenalty(ctx context.Context, q *db.Queries, solve solvePoints, points float64) (attempts int, penalty float64, err error) {
+	if solve.problem.WrongAnswerPenalty.IsZero() {
+		return 0, 0, nil
+	}
+
+	n, err := q.CountIncorrectSubmissions(ctx, db.CountIncorrectSubmissionsParams{
+		TeamName:  solve.teamName,
+		ProblemID: solve.problemID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count incorrect submissions: %w", err)
+	}
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	attempts = int(n)
+	penalty = wrongAnswerPenalty(solve, points, attempts)
+	if penalty <= 0 {
+		return attempts, 0, nil
+	}
+
+	err = addSolvePoints(ctx, q, solve, solve.teamName, -penalty, PointsFromPenalty)
+	return attempts, penalty, err
+}
+
+// adjustWrongAnswerPenalty recomputes the wrong answer penalty of an earlier
+// solver whose points changed to the given points, given the penalty that it
+// was deducted so far. This keeps the penalty from exceeding the points of
+// the solve when solve count scaling lowers them.
+func adjustWrongAnswerPenalty(ctx context.Context, q *db.Queries, solve solvePoints, teamName string, points, deducted float64) error {
+	if solve.problem.WrongAnswerPenalty.IsZero() {
+		return nil
+	}
+
+	n, err := q.CountIncorrectSubmissions(ctx, db.CountIncorrectSubmissionsParams{
+		TeamName:  teamName,
+		ProblemID: solve.problemID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count incorrect submissions: %w", err)
+	}
+
+	// Penalty minutes can't be used with solve count scaling, so only the
+	// penalty points matter.
+	penalty := points - solve.problem.WrongAnswerPenalty.Apply(points, int(n))
+	if math.Abs(penalty-deducted) < 1e-9 {
+		return nil
+	}
+
+	return addSolvePoints(ctx, q, solve, teamName, deducted-penalty, PointsFromPenalty)
+}
+
+// timedPoints returns the points of a solve made at the given time, scaled by
+// the time since the team's score decay started.
+func timedPoints(solve solvePoints, t time.Time) float64 {
+	return solve.problem.ScalePoints(t, solve.startedAt)
+}
+
+// decayStart returns when the team's score decay of the problem starts. This
+// is the release time of the problem, unless the problem measures the decay
+// from when the team first opened it.
+func decayStart(ctx context.Context, q *db.Queries, division *Division, p *problem.Problem, day problemDay, teamName string) (time.Time, error) {
+	releasedAt := division.Problems.ProblemStartTime(day.index())
+	if !p.DecayFromFirstOpen {
+		return releasedAt, nil
+	}
+
+	openedAt, err := q.ProblemStartTime(ctx, db.ProblemStartTimeParams{
+		TeamName:  teamName,
+		ProblemID: p.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, fmt.Errorf("failed to get problem start time: %w", err)
+	}
+
+	return p.DecayStart(releasedAt, openedAt.Time()), nil
+}
+
+// wrongAnswerPenalty returns the points deducted from a solve worth the given
+// points for the incorrect attempts made before it.
+func wrongAnswerPenalty(solve solvePoints, points float64, attempts int) float64 {
+	penalty := solve.problem.WrongAnswerPenalty
+	penalized := points
+	if delay := penalty.Delay(attempts); delay > 0 {
+		penalized = timedPoints(solve, solve.solvedAt.Add(delay))
+	}
+	return points - penalty.Apply(penalized, attempts)
+}
+
+// addSolvePoints adds a ledger entry to the team for the solve. The team may
+// differ from the solver, e.g. for adjustments to earlier solvers.
+func addSolvePoints(ctx context.Context, q *db.Queries, solve solvePoints, teamName string, points float64, source PointsSource) error {
+	return addPoints(ctx, q, db.AddPointsParams{
+		TeamName:     teamName,
+		Points:       points,
+		Reason:       source.Label(),
+		Source:       string(source),
+		ProblemID:    sql.NullString{String: solve.problemID, Valid: true},
+		Part:         sql.NullInt64{Int64: int64(solve.part), Valid: true},
+		SubmissionID: sql.NullInt64{Int64: solve.submissionID, Valid: solve.submissionID != 0},
+		AddedAt:      db.NewDateTime(solve.solvedAt),
+	})
+}
+
+// addPoints adds an entry to the points ledger.
+func addPoints(ctx context.Context, q *db.Queries, entry db.AddPointsParams) error {
+	if _, err := q.AddPoints(ctx, entry); err != nil {
+		return fmt.Errorf("failed to add points for team %q: %w", entry.TeamName, err)
+	}
+	return nil
+}
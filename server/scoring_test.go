@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"dev.acmcsuf.com/march-madness-2024/server/problem"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSolveCountPenaltyNeverCostsPoints(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	database, err := db.NewInMemory()
+	assert.NoError(t, err)
+	defer database.Close()
+
+	p := problem.NewProblem("problem", problem.ProblemDescription{}, nil, problem.ProblemConfig{
+		ScoringVersion:     problem.V3SolveCountScaling,
+		WrongAnswerPenalty: problem.WrongAnswerPenalty{Points: 99.9},
+	})
+	division := &Division{Problems: problem.NewProblemSet([]problem.Problem{p})}
+	s := &Server{}
+
+	for i, team := range []string{"a", "b"} {
+		solvedAt := now.Add(time.Duration(i) * time.Hour)
+
+		err := database.Tx(func(q *db.Queries) error {
+			_, err := q.CreateTeam(ctx, db.CreateTeamParams{
+				TeamName:   team,
+				InviteCode: team,
+				CreatedAt:  db.NewDateTime(now),
+			})
+			assert.NoError(t, err)
+
+			var submission db.TeamSubmitAttempt
+			for _, correct := range []bool{false, true} {
+				submission, err = q.RecordSubmission(ctx, db.RecordSubmissionParams{
+					TeamName:    team,
+					ProblemID:   "problem/part1",
+					Correct:     correct,
+					SubmittedAt: db.NewDateTime(solvedAt),
+				})
+				assert.NoError(t, err)
+			}
+
+			_, err = s.awardSolvePoints(ctx, q, solvePoints{
+				division:     division,
+				problem:      &p,
+				day:          1,
+				problemID:    "problem/part1",
+				part:         1,
+				teamName:     team,
+				submissionID: submission.ID,
+				solvedAt:     solvedAt,
+			})
+			return err
+		})
+		assert.NoError(t, err)
+	}
+
+	rows, err := database.ProblemPointsByTeam(ctx, db.ProblemPointsByTeamParams{
+		ProblemID: sql.NullString{String: "problem/part1", Valid: true},
+	})
+	assert.NoError(t, err)
+
+	// The second solve lowers the points of the part below the penalty of the
+	// first solver, so its penalty must shrink with them.
+	net := make(map[string]float64)
+	for _, row := range rows {
+		net[row.TeamName] += row.Points.Float64
+	}
+	for _, team := range []string{"a", "b"} {
+		assert.True(t, net[team] > -1e-9, "team %s must not lose points, got %v", team, net[team])
+	}
+}
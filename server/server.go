@@ -2,11 +2,14 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	"dev.acmcsuf.com/march-madness-2024/internal/clock"
 	"dev.acmcsuf.com/march-madness-2024/internal/config"
 	"dev.acmcsuf.com/march-madness-2024/server/db"
 	"dev.acmcsuf.com/march-madness-2024/server/frontend"
@@ -21,31 +24,56 @@ type Server struct {
 	*chi.Mux
 	config ServerConfig
 
-	problems *problem.ProblemSet
+	live     atomic.Pointer[ReloadableConfig]
 	template *tmplutil.Templater
 	database *db.Database
 	logger   *slog.Logger
+	clock    clock.Clock
 }
 
 type ServerConfig struct {
-	FrontendDir          fs.FS
-	SecretKey            SecretKey
-	Problems             *problem.ProblemSet
-	Database             *db.Database
-	Logger               *slog.Logger
+	FrontendDir fs.FS
+	SecretKey   SecretKey
+	Database    *db.Database
+	Logger      *slog.Logger
+	// Clock tells the competition time. It defaults to the system clock.
+	Clock clock.Clock
+	// Reload is called by the admin reload endpoint. It should re-read the
+	// configuration and pass it to [Server.Reload]. If nil, the endpoint is
+	// disabled.
+	Reload func(ctx context.Context) error
+
+	ReloadableConfig
+}
+
+// ReloadableConfig is the part of the server configuration that can be
+// replaced while the server is running. See [Server.Reload].
+type ReloadableConfig struct {
+	// Divisions are the divisions of the competition. There must be at least
+	// one division. The first division is shown to users without a team.
+	Divisions            []Division
 	HackathonConfig      config.HackathonConfig
 	OpenRegistrationTime time.Time
+	// AdminToken is the bearer token required by the admin endpoints. If
+	// empty, the admin endpoints are disabled.
+	AdminToken  string
+	Leaderboard config.LeaderboardConfig
 }
 
 // New creates a new server.
 func New(config ServerConfig) *Server {
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
 	s := &Server{
 		config:   config,
-		template: frontend.NewTemplater(config.FrontendDir),
-		problems: config.Problems,
+		template: frontend.NewTemplater(config.FrontendDir, config.Clock),
 		database: config.Database,
 		logger:   config.Logger,
+		clock:    config.Clock,
 	}
+	s.live.Store(&config.ReloadableConfig)
 
 	s.Mux = chi.NewRouter()
 	r := s.Mux
@@ -65,8 +93,14 @@ func New(config ServerConfig) *Server {
 		r.Route("/problems", s.routeProblems)
 		r.Route("/hackathon", s.routeHackathon)
 		r.Route("/leaderboard", s.routeLeaderboard)
+		r.Route("/divisions/{division}", func(r chi.Router) {
+			r.Route("/problems", s.routeProblems)
+			r.Route("/leaderboard", s.routeLeaderboard)
+		})
 	})
 
+	r.Route("/admin", s.routeAdmin)
+
 	r.Route("/static", func(r chi.Router) {
 		r.Use(middleware.Compress(5))
 		r.Use(middleware.SetHeader("Cache-Control", "public, must-revalidate"))
@@ -110,6 +144,8 @@ func writeError(w http.ResponseWriter, code int, err error) {
 type indexPageData struct {
 	frontend.ComponentContext
 	Problems        *problem.ProblemSet
+	Division        *Division
+	Divisions       []Division
 	HackathonConfig config.HackathonConfig
 	InviteCode      string
 }
@@ -127,13 +163,21 @@ func (s *Server) index(w http.ResponseWriter, r *http.Request) {
 		inviteCode = code
 	}
 
+	division, err := s.requestDivision(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
 	s.renderTemplate(w, "index", indexPageData{
 		ComponentContext: frontend.ComponentContext{
 			TeamName: u.TeamName,
 			Username: u.Username,
 		},
-		Problems:        s.problems,
-		HackathonConfig: s.config.HackathonConfig,
+		Problems:        division.Problems,
+		Division:        division,
+		Divisions:       s.Divisions(),
+		HackathonConfig: s.live.Load().HackathonConfig,
 		InviteCode:      inviteCode,
 	})
 }
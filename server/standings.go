@@ -0,0 +1,344 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+)
+
+// TeamStanding is the place of a team in the standings of its division, along
+// with the values that its ties are broken by.
+type TeamStanding struct {
+	TeamName string
+	Points   float64
+	// Rank is the place of the team, starting at 1. Teams that are tied on
+	// their points and on every tiebreaker share a rank.
+	Rank int
+	// ReachedAt is when the team reached its points with its own solves, or
+	// when it was created if it has none. Points that the team didn't earn
+	// with its own submissions, such as adjustments caused by other teams'
+	// solves, voids and corrections, don't move it.
+	ReachedAt time.Time
+	// Solves is the number of problem parts that the team solved.
+	Solves int
+	// WrongAnswers is the number of wrong answers that the team submitted.
+	WrongAnswers int
+
+	// hasPoints is true if the team has any entries in the points ledger.
+	hasPoints bool
+}
+
+// TiebreakValue describes the value of the team for the given tiebreaker.
+func (s TeamStanding) TiebreakValue(tiebreaker config.Tiebreaker) string {
+	switch tiebreaker {
+	case config.TiebreakEarliest:
+		return "reached points at " + s.ReachedAt.Local().Format("Jan 2 15:04:05")
+	case config.TiebreakMostSolves:
+		return fmt.Sprintf("parts solved: %d", s.Solves)
+	case config.TiebreakFewestWrong:
+		return fmt.Sprintf("wrong answers: %d", s.WrongAnswers)
+	default:
+		return string(tiebreaker)
+	}
+}
+
+// Standings returns the ranked standings of every team in the division.
+func Standings(ctx context.Context, q *db.Queries, division string, tiebreakers []config.Tiebreaker) ([]TeamStanding, error) {
+	data, err := loadStandingsData(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := data.standings(division, nil)
+	RankStandings(standings, tiebreakers)
+	return standings, nil
+}
+
+// standingsData is everything that standings are computed from.
+type standingsData struct {
+	teams       []db.ListTeamsRow
+	points      []db.TeamPoint
+	submissions []db.TeamSubmitAttempt
+}
+
+func loadStandingsData(ctx context.Context, q *db.Queries) (standingsData, error) {
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return standingsData{}, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	points, err := q.ListPoints(ctx)
+	if err != nil {
+		return standingsData{}, fmt.Errorf("failed to list points: %w", err)
+	}
+
+	submissions, err := q.ListAllSubmissions(ctx)
+	if err != nil {
+		return standingsData{}, fmt.Errorf("failed to list submissions: %w", err)
+	}
+
+	return standingsData{teams, points, submissions}, nil
+}
+
+// standings computes the unranked standings of every team in the division. If
+// visible is not nil, only the points and submissions of a team at the times
+// for which it returns true are counted.
+func (d standingsData) standings(division string, visible func(team string, t time.Time) bool) []TeamStanding {
+	if visible == nil {
+		visible = func(string, time.Time) bool { return true }
+	}
+
+	var standings []TeamStanding
+	indices := make(map[string]int)
+	for _, team := range d.teams {
+		if team.Division != division {
+			continue
+		}
+		indices[team.TeamName] = len(standings)
+		standings = append(standings, TeamStanding{
+			TeamName:  team.TeamName,
+			ReachedAt: team.CreatedAt.Time(),
+		})
+	}
+
+	// The time at which each team reached its points can only be known
+	// after its final points are, so the ledger is walked twice.
+	points := slices.Clone(d.points)
+	slices.SortStableFunc(points, func(a, b db.TeamPoint) int {
+		return a.AddedAt.Time().Compare(b.AddedAt.Time())
+	})
+
+	for _, entry := range points {
+		i, ok := indices[entry.TeamName]
+		if !ok {
+			continue
+		}
+		standings[i].hasPoints = true
+		if visible(entry.TeamName, entry.AddedAt.Time()) {
+			standings[i].Points += entry.Points
+		}
+	}
+
+	submitters := make(map[int64]string, len(d.submissions))
+	for _, submission := range d.submissions {
+		submitters[submission.ID] = submission.TeamName
+	}
+
+	running := make([]float64, len(standings))
+	for _, entry := range points {
+		i, ok := indices[entry.TeamName]
+		if !ok || !visible(entry.TeamName, entry.AddedAt.Time()) {
+			continue
+		}
+		wasReached := pointsEqual(running[i], standings[i].Points)
+		running[i] += entry.Points
+		own := entry.SubmissionID.Valid && submitters[entry.SubmissionID.Int64] == entry.TeamName
+		if own && (!wasReached || !pointsEqual(running[i], standings[i].Points)) {
+			standings[i].ReachedAt = entry.AddedAt.Time()
+		}
+	}
+
+	solved := make(map[string]bool)
+	for _, submission := range d.submissions {
+		i, ok := indices[submission.TeamName]
+		if !ok || !visible(submission.TeamName, submission.SubmittedAt.Time()) {
+			continue
+		}
+		switch {
+		case !submission.Correct:
+			standings[i].WrongAnswers++
+		case !solved[submission.TeamName+"\x00"+submission.ProblemID]:
+			solved[submission.TeamName+"\x00"+submission.ProblemID] = true
+			standings[i].Solves++
+		}
+	}
+
+	return standings
+}
+
+// countSolves returns the number of teams in the division that solved the
+// problem part, only counting the solves for which visible returns true.
+func (d standingsData) countSolves(division, problemID string, visible func(team string, t time.Time) bool) int {
+	inDivision := make(map[string]bool, len(d.teams))
+	for _, team := range d.teams {
+		if team.Division == division {
+			inDivision[team.TeamName] = true
+		}
+	}
+
+	solved := make(map[string]bool)
+	for _, submission := range d.submissions {
+		if submission.Correct && submission.ProblemID == problemID &&
+			inDivision[submission.TeamName] && visible(submission.TeamName, submission.SubmittedAt.Time()) {
+			solved[submission.TeamName] = true
+		}
+	}
+	return len(solved)
+}
+
+// RankStandings sorts the standings from the first place to the last and sets
+// their ranks. Teams are ranked by their points, then by each tiebreaker in
+// order. Points are compared as they are displayed, so teams that are shown
+// with the same points are tied. Teams that are still tied are listed by name.
+func RankStandings(standings []TeamStanding, tiebreakers []config.Tiebreaker) {
+	compare := func(a, b TeamStanding) int {
+		if c := cmp.Compare(displayedPoints(b.Points), displayedPoints(a.Points)); c != 0 {
+			return c
+		}
+		for _, tiebreaker := range tiebreakers {
+			var c int
+			switch tiebreaker {
+			case config.TiebreakEarliest:
+				c = a.ReachedAt.Compare(b.ReachedAt)
+			case config.TiebreakMostSolves:
+				c = cmp.Compare(b.Solves, a.Solves)
+			case config.TiebreakFewestWrong:
+				c = cmp.Compare(a.WrongAnswers, b.WrongAnswers)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	slices.SortFunc(standings, func(a, b TeamStanding) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+
+	for i := range standings {
+		if i > 0 && compare(standings[i-1], standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+}
+
+// displayedPoints returns the points as they are displayed, rounded down to
+// whole points.
+func displayedPoints(points float64) float64 {
+	return math.Floor(points)
+}
+
+// pointsEqual returns true if the points are equal, ignoring the rounding
+// errors of summing up fractional points.
+func pointsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// UserStanding is the place of a user in the individual standings of their
+// division. Each correct part is credited to the user who submitted it,
+// along with the points that their team was awarded for it.
+type UserStanding struct {
+	Username string
+	TeamName string
+	Points   float64
+	// Solves is the number of problem parts that the user solved.
+	Solves int
+	// Rank is the place of the user, starting at 1. Users with the same
+	// points and solves share a rank.
+	Rank int
+}
+
+// userStandings computes the ranked individual standings of the division.
+// Voided parts are not credited to anyone. If visible is not nil, only the
+// points and submissions of a team at the times for which it returns true are
+// counted.
+func (d standingsData) userStandings(division string, voided []db.VoidedProblem, visible func(team string, t time.Time) bool) []UserStanding {
+	if visible == nil {
+		visible = func(string, time.Time) bool { return true }
+	}
+
+	inDivision := make(map[string]bool, len(d.teams))
+	for _, team := range d.teams {
+		if team.Division == division {
+			inDivision[team.TeamName] = true
+		}
+	}
+
+	isVoided := make(map[string]bool, len(voided))
+	for _, v := range voided {
+		if v.Division == division {
+			isVoided[v.ProblemID] = true
+		}
+	}
+
+	// Only the points that a part itself earned are credited, not the
+	// points that admins added or the credit for a voided part.
+	type teamPart struct{ teamName, problemID string }
+	partPoints := make(map[teamPart]float64)
+	for _, entry := range d.points {
+		switch PointsSource(entry.Source) {
+		case PointsFromSolve, PointsFromPenalty, PointsFromFirstSolve:
+		default:
+			continue
+		}
+		if !entry.ProblemID.Valid || !visible(entry.TeamName, entry.AddedAt.Time()) {
+			continue
+		}
+		partPoints[teamPart{entry.TeamName, entry.ProblemID.String}] += entry.Points
+	}
+
+	var standings []UserStanding
+	indices := make(map[string]int)
+	credited := make(map[teamPart]bool)
+	for _, submission := range d.submissions {
+		part := teamPart{submission.TeamName, submission.ProblemID}
+		switch {
+		case !submission.Correct || !submission.SubmittedBy.Valid:
+			continue
+		case !inDivision[submission.TeamName] || isVoided[submission.ProblemID]:
+			continue
+		case credited[part] || !visible(submission.TeamName, submission.SubmittedAt.Time()):
+			continue
+		}
+		credited[part] = true
+
+		i, ok := indices[submission.SubmittedBy.String]
+		if !ok {
+			i = len(standings)
+			indices[submission.SubmittedBy.String] = i
+			standings = append(standings, UserStanding{
+				Username: submission.SubmittedBy.String,
+				TeamName: submission.TeamName,
+			})
+		}
+		standings[i].Points += partPoints[part]
+		standings[i].Solves++
+	}
+
+	compare := func(a, b UserStanding) int {
+		if c := cmp.Compare(displayedPoints(b.Points), displayedPoints(a.Points)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Solves, a.Solves)
+	}
+
+	slices.SortFunc(standings, func(a, b UserStanding) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	for i := range standings {
+		if i > 0 && compare(standings[i-1], standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
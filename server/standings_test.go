@@ -0,0 +1,80 @@
+package server
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"dev.acmcsuf.com/march-madness-2024/internal/config"
+	"dev.acmcsuf.com/march-madness-2024/server/db"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRankStandings(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	standings := []TeamStanding{
+		{TeamName: "a", Points: 100.2, ReachedAt: now.Add(time.Hour)},
+		{TeamName: "b", Points: 100.9, ReachedAt: now},
+		{TeamName: "c", Points: 200, ReachedAt: now.Add(2 * time.Hour)},
+		{TeamName: "d", Points: 50, ReachedAt: now, Solves: 2},
+		{TeamName: "e", Points: 50, ReachedAt: now, Solves: 2},
+		{TeamName: "f", Points: 50, ReachedAt: now, Solves: 3},
+	}
+	RankStandings(standings, config.DefaultTiebreakers)
+
+	type ranked struct {
+		TeamName string
+		Rank     int
+	}
+	var got []ranked
+	for _, standing := range standings {
+		got = append(got, ranked{standing.TeamName, standing.Rank})
+	}
+
+	// a and b are both shown with 100 points, so b wins by reaching them
+	// first even though it has more fractional points. d and e are tied on
+	// everything and share their rank.
+	assert.Equal(t, []ranked{
+		{"c", 1},
+		{"b", 2},
+		{"a", 3},
+		{"f", 4},
+		{"d", 5},
+		{"e", 5},
+	}, got)
+}
+
+func TestStandingsReachedAt(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) db.DateTime {
+		return db.NewDateTime(now.Add(time.Duration(hours) * time.Hour))
+	}
+	submissionID := func(id int64) sql.NullInt64 {
+		return sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	data := standingsData{
+		teams: []db.ListTeamsRow{
+			{TeamName: "a", Division: "div", CreatedAt: at(0)},
+			{TeamName: "b", Division: "div", CreatedAt: at(0)},
+		},
+		submissions: []db.TeamSubmitAttempt{
+			{ID: 1, TeamName: "a", ProblemID: "p/part1", Correct: true, SubmittedAt: at(1)},
+			{ID: 2, TeamName: "b", ProblemID: "p/part1", Correct: true, SubmittedAt: at(2)},
+		},
+		points: []db.TeamPoint{
+			{TeamName: "a", Points: 100, Source: "solve", SubmissionID: submissionID(1), AddedAt: at(1)},
+			// The solve of b lowers the points of a.
+			{TeamName: "a", Points: -20, Source: "solve", SubmissionID: submissionID(2), AddedAt: at(2)},
+			{TeamName: "b", Points: 80, Source: "solve", SubmissionID: submissionID(2), AddedAt: at(2)},
+			// Corrections don't count as reaching points either.
+			{TeamName: "b", Points: 5, Source: "admin", AddedAt: at(3)},
+		},
+	}
+
+	standings := data.standings("div", nil)
+	assert.Equal(t, 2, len(standings))
+	assert.Equal(t, at(1).Time(), standings[0].ReachedAt)
+	assert.Equal(t, at(2).Time(), standings[1].ReachedAt)
+}